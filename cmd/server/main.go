@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"gitlab.com/YSX/eventloop/internal/httpapi"
 	"gitlab.com/YSX/eventloop/internal/loggerImplementation"
@@ -15,8 +16,9 @@ import (
 )
 
 const (
-	_LOGLEVEL = "debug"
-	_PORT     = 8090
+	_LOGLEVEL        = "debug"
+	_PORT            = 8090
+	_SHUTDOWNTIMEOUT = 10 * time.Second
 )
 
 // @title			Event Loop API
@@ -35,7 +37,7 @@ func inputMonitor(sc chan<- os.Signal) {
 func main() {
 
 	srvLogger, err := initLogger()
-	evLoop := eventloop.NewEventLoop(srvLogger.Level())
+	evLoop := eventloop.NewEventLoop(eventloop.WithLogger(srvLogger))
 
 	if err != nil {
 		fmt.Println(err)
@@ -66,6 +68,13 @@ func main() {
 		fmt.Println(errStop)
 	}
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, _SHUTDOWNTIMEOUT)
+	defer shutdownCancel()
+	report, errShutdown := evLoop.Shutdown(shutdownCtx)
+	if errShutdown != nil {
+		fmt.Printf("Event loop stopped with error: %v, aborted events: %v\n", errShutdown, report.Aborted)
+	}
+
 	fmt.Println("Server stopped.")
 }
 
@@ -28,18 +28,9 @@ var (
 func StartServer(port int, evLoop eventloop.Interface, srvLogger logger.Interface) error {
 	helper.APIMessageSetPrefix(_APIPREFIX)
 
-	handlersMap := map[string]handler.Type{
-		"/events/":    handler.EVENT,
-		"/trigger/":   handler.TRIGGER,
-		"/subscribe/": handler.SUBSCRIBE,
-		"/toggle/":    handler.TOGGLE,
-		"/scheduler/": handler.SCHEDULER,
-	}
-
-	mux := http.NewServeMux()
-	for k, v := range handlersMap {
-		mux.Handle(k, handler.NewHandler(v, srvLogger, evLoop))
-	}
+	schedules := handler.NewSchedules()
+	mux := newMux(srvLogger, evLoop, "", schedules)
+	tenants := newTenantRouter(mux, srvLogger, evLoop, schedules)
 
 	// Swagger
 	docs.SwaggerInfo.Host = fmt.Sprintf(docs.SwaggerInfo.Host, port)
@@ -53,7 +44,7 @@ func StartServer(port int, evLoop eventloop.Interface, srvLogger logger.Interfac
 
 	serv = http.Server{
 		Addr:         ":" + strconv.Itoa(port),
-		Handler:      mux,
+		Handler:      tenants,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -68,6 +59,28 @@ func StartServer(port int, evLoop eventloop.Interface, srvLogger logger.Interfac
 	return nil
 }
 
+// newMux создаёт маршруты API для менеджера событий evLoop пространства имён namespace ("" - общего)
+func newMux(
+	srvLogger logger.Interface, evLoop eventloop.Interface, namespace string, schedules *handler.Schedules,
+) *http.ServeMux {
+	handlersMap := map[string]handler.Type{
+		"/events/":    handler.EVENT,
+		"/trigger/":   handler.TRIGGER,
+		"/subscribe/": handler.SUBSCRIBE,
+		"/toggle/":    handler.TOGGLE,
+		"/scheduler/": handler.SCHEDULER,
+		"/pause/":     handler.PAUSE,
+		"/resume/":    handler.RESUME,
+		"/cancel/":    handler.CANCEL,
+	}
+
+	mux := http.NewServeMux()
+	for k, v := range handlersMap {
+		mux.Handle(k, handler.NewNamespaceHandler(v, srvLogger, evLoop, namespace, schedules))
+	}
+	return mux
+}
+
 func StopServer(ctx context.Context, srvLogger logger.Interface) error {
 	err := serv.Shutdown(ctx)
 	if err != nil {
@@ -3,13 +3,18 @@ package httpapi
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"gitlab.com/YSX/eventloop/internal/httpapi/handler"
 	loggerImplement "gitlab.com/YSX/eventloop/internal/loggerImplementation"
 	"gitlab.com/YSX/eventloop/pkg/eventloop"
 	"gitlab.com/YSX/eventloop/pkg/logger"
@@ -97,6 +102,114 @@ func TestEventRemove(t *testing.T) {
 	}
 }
 
+func TestEventPauseResumeCancel(t *testing.T) {
+	const (
+		EVENTNAME = "test_pause"
+		WANT      = "1"
+	)
+
+	_, id := createEvent(t, EVENTNAME)
+
+	if missing := controlEvents(t, "pause", []string{id}); missing != "[]" {
+		t.Errorf("Pause missing: %v, WANT: []", missing)
+	}
+	if result := triggerEvents(t, EVENTNAME); result != "" {
+		t.Errorf("Paused result: %v, WANT: empty", result)
+	}
+
+	controlEvents(t, "resume", []string{id})
+	if result := triggerEvents(t, EVENTNAME); result != WANT {
+		t.Errorf("Resumed result: %v, WANT: %v", result, WANT)
+	}
+
+	controlEvents(t, "cancel", []string{id})
+	if missing := controlEvents(t, "pause", []string{id}); missing != fmt.Sprintf("[%q]", id) {
+		t.Errorf("Pause after cancel missing: %v, WANT: [%q]", missing, id)
+	}
+}
+
+func TestEventTenants(t *testing.T) {
+	const EVENTNAME = "test_tenant"
+
+	// trigger вызывает триггер по пути path с заголовком арендатора tenant и возвращает код ответа и тело
+	trigger := func(path, tenant string) (int, string) {
+		request, err := http.NewRequest(http.MethodPost, "http://localhost:8090"+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tenant != "" {
+			request.Header.Set(TenantHeader, tenant)
+		}
+		resp, err := http.DefaultClient.Do(request)
+		return resp.StatusCode, handleRequest(t, resp, err)
+	}
+
+	resp, err := http.PostForm("http://localhost:8090/tenants/acme/events/1/"+EVENTNAME, url.Values{})
+	if handleRequest(t, resp, err); resp.StatusCode != 200 {
+		t.Fatalf("tenant event is not created: %v", resp.Status)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		tenant     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Header",
+			path:       "/trigger/" + EVENTNAME,
+			tenant:     "acme",
+			wantStatus: 200,
+			wantBody:   "1",
+		},
+		{
+			name:       "Path prefix",
+			path:       "/tenants/acme/trigger/" + EVENTNAME,
+			wantStatus: 200,
+			wantBody:   "2",
+		},
+		{
+			// Без арендатора нельзя обратиться к триггеру пространства имён по его внутреннему имени
+			name:       "Reserved trigger name",
+			path:       "/trigger/$acme." + EVENTNAME,
+			wantStatus: 400,
+		},
+		{
+			name:       "Shared namespace",
+			path:       "/trigger/" + EVENTNAME,
+			wantStatus: 204,
+		},
+		{
+			name:       "Other tenant",
+			path:       "/tenants/globex/trigger/" + EVENTNAME,
+			wantStatus: 204,
+		},
+		{
+			name:       "Path and header differ",
+			path:       "/tenants/acme/trigger/" + EVENTNAME,
+			tenant:     "globex",
+			wantStatus: 400,
+		},
+		{
+			name:       "Invalid tenant",
+			path:       "/trigger/" + EVENTNAME,
+			tenant:     "acme.eu",
+			wantStatus: 400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				status, body := trigger(tt.path, tt.tenant)
+				if status != tt.wantStatus || tt.wantBody != "" && body != tt.wantBody {
+					t.Errorf("status = %v, body = %q, want %v, %q", status, body, tt.wantStatus, tt.wantBody)
+				}
+			},
+		)
+	}
+}
+
 func TestEventSubscribe(t *testing.T) {
 	const (
 		EVENTNAME = "test_subscribe"
@@ -126,23 +239,95 @@ func TestEventSubscribe(t *testing.T) {
 	}
 }
 
-func TestEventSchedule(t *testing.T) {
-	const (
-		WANT = "1"
+func TestEventUnsubscribe(t *testing.T) {
+	const EVENTNAME = "test_unsubscribe"
+
+	encodedJSON, _ := json.Marshal(
+		struct {
+			Listeners []int `json:"listeners"`
+			Triggers  []int `json:"triggers"`
+		}{Listeners: []int{1}, Triggers: []int{1, 2}},
 	)
+	resp, err := http.Post(
+		"http://localhost:8090/subscribe/"+EVENTNAME, "application/json", bytes.NewReader(encodedJSON),
+	)
+	subscribed := handleJsonRequest[handler.SubscribeResponse](t, resp, err)
+	if len(subscribed.Triggers) != 2 || len(subscribed.Listeners) != 1 {
+		t.Fatalf("Subscribe response: %+v", subscribed)
+	}
 
-	if respStart, result := scheduleEvent(t, bytes.NewBufferString("START")); respStart.StatusCode != 200 {
-		t.Errorf("Server not started or event is not created: %v", result)
+	tests := []struct {
+		name  string
+		links handler.SubscribeResponse
+		want  []string
+	}{
+		{
+			name: "One trigger and unknown",
+			links: handler.SubscribeResponse{
+				Triggers: []string{subscribed.Triggers[0], "unknown"}, Listeners: subscribed.Listeners,
+			},
+			want: []string{"unknown"},
+		},
+		{
+			name: "Already unsubscribed",
+			links: handler.SubscribeResponse{
+				Triggers: subscribed.Triggers[:1], Listeners: subscribed.Listeners,
+			},
+			want: subscribed.Triggers[:1],
+		},
+		{
+			name:  "Last link",
+			links: handler.SubscribeResponse{Triggers: subscribed.Triggers[1:], Listeners: subscribed.Listeners},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := unsubscribeEvents(t, tt.links); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Unsubscribe response: %v, WANT: %v", got, tt.want)
+				}
+			},
+		)
 	}
-	time.Sleep(time.Millisecond * 600)
+	// Триггеры без слушателей выполняются и не ждут остановленных горутин
+	if got := triggerEvents(t, EVENTNAME); got == "" {
+		t.Error("Trigger after unsubscribe returned nothing")
+	}
+}
 
-	resp, JSON := scheduleStop(t)
+func TestEventSchedule(t *testing.T) {
+	const (
+		WANT = "1"
+	)
 
-	if resp.StatusCode != 200 {
-		t.Errorf("Response status: %v", resp.StatusCode)
+	tests := []struct {
+		name   string
+		prefix string
+	}{
+		{name: "Shared namespace"},
+		// Маршруты арендатора создаются на каждый запрос, запущенные события должны дожить до остановки
+		{name: "Tenant", prefix: "/tenants/acme"},
 	}
-	if JSON.Result[0] != WANT {
-		t.Errorf("WANT: %v; Result: %v", WANT, JSON.Result[0])
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				respStart, result := scheduleEvent(t, tt.prefix, bytes.NewBufferString("START"))
+				if respStart.StatusCode != 200 {
+					t.Errorf("Server not started or event is not created: %v", result)
+				}
+				time.Sleep(time.Millisecond * 600)
+
+				resp, JSON := scheduleStop(t, tt.prefix)
+
+				if resp.StatusCode != 200 {
+					t.Errorf("Response status: %v", resp.StatusCode)
+				}
+				if len(JSON.Result) != 1 || JSON.Result[0] != WANT {
+					t.Errorf("WANT: %v; Result: %v", WANT, JSON.Result)
+				}
+			},
+		)
 	}
 }
 
@@ -156,7 +341,7 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
-	evLoop := eventloop.NewEventLoop(testLogger.Level())
+	evLoop := eventloop.NewEventLoop(eventloop.WithLogger(testLogger))
 
 	go func() {
 		errServ := StartServer(8090, evLoop, testLogger)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
 )
 
 type (
@@ -17,19 +18,32 @@ type (
 const (
 	REGULAR EventType = iota + 1
 	INTERVALED
+	// SUBSCRIBE_TRIGGER и SUBSCRIBE_LISTENER - событие-триггер и событие-слушатель для Subscribe
+	SUBSCRIBE_TRIGGER
+	SUBSCRIBE_LISTENER
 )
 
 var Events = [...]EventFunc{event1, event2}
 
-// CreateEvent создаёт событие из пресета id с типом eventType (типы REGULAR, INTERVALED).
-// Возвращает ошибку, если такого пресета нет
+// CreateEvent создаёт событие из пресета id с типом eventType (типы REGULAR, INTERVALED, SUBSCRIBE_TRIGGER,
+// SUBSCRIBE_LISTENER) в пространстве имён namespace ("" - общее). Возвращает ошибку, если такого пресета нет
 // Интервал интервального ивента 500 ms
-func CreateEvent(id int, eventType EventType, triggerName string) (event.Interface, error) {
+func CreateEvent(id int, eventType EventType, triggerName, namespace string) (event.Interface, error) {
 	switch eventType {
 	case REGULAR:
-		return event.NewEvent(event.Args{Fun: Events[id-1](), TriggerName: triggerName})
+		return event.NewEvent(event.Args{Fun: Events[id-1](), TriggerName: triggerName, Namespace: namespace})
 	case INTERVALED:
-		return event.NewEvent(event.Args{Fun: Events[id-1](), IntervalTime: 500 * time.Millisecond})
+		return event.NewEvent(
+			event.Args{Fun: Events[id-1](), IntervalTime: 500 * time.Millisecond, Namespace: namespace},
+		)
+	case SUBSCRIBE_TRIGGER:
+		return event.NewEvent(
+			event.Args{
+				Fun: Events[id-1](), TriggerName: triggerName, Namespace: namespace, Subscriber: subscriber.Trigger,
+			},
+		)
+	case SUBSCRIBE_LISTENER:
+		return event.NewEvent(event.Args{Fun: Events[id-1](), Namespace: namespace, Subscriber: subscriber.Listener})
 	default:
 		return nil, fmt.Errorf("No such type: %v", eventType)
 	}
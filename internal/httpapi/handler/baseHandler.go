@@ -8,4 +8,6 @@ import (
 type baseHandler struct {
 	logger logger.Interface
 	evLoop eventloop.Interface
+	// namespace - арендатор, в пространстве имён которого создаются события, "" - общее пространство
+	namespace string
 }
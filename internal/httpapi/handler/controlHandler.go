@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"gitlab.com/YSX/eventloop/internal/httpapi/helper"
+)
+
+// controlHandler ставит события на паузу, снимает с паузы и отменяет. Отправляется POST запрос на /pause/, /resume/
+// или /cancel/ с JSON массивом UUID событий
+type controlHandler struct {
+	baseHandler
+	// name - название действия для логов
+	name string
+	// control - функция менеджера событий, возвращает UUID из запроса, событий с которыми нет
+	control func(uUIDs ...string) []string
+}
+
+// ServeHTTP godoc
+//
+//	@Summary	Pause, resume or cancel events by UUIDs
+//	@Tags		events
+//	@Accept		json
+//	@Produce	json
+//	@Param		{uuids}	body		[]string	true	"UUIDs of events"
+//	@Success	200		{array}		[]string	"Array of UUIDs from request with no such events"
+//	@Failure	400		{string}	string		"Something wrong with request"
+//	@Failure	405		{string}	string		"only POST allowed"
+//	@Router		/pause/ [post]
+//	@Router		/resume/ [post]
+//	@Router		/cancel/ [post]
+func (ch *controlHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != "POST" {
+		helper.NoMethodResponse(writer, "POST")
+		ch.logger.Infof(helper.APIMessage("[%s] No such method: %s"), ch.name, request.Method)
+		return
+	}
+
+	b, err := io.ReadAll(request.Body)
+	if err != nil {
+		helper.ServerLogErr(writer, "Error reading request: %v", ch.logger, 400, err)
+		return
+	}
+	var sl []uuid.UUID
+	if errJSON := json.Unmarshal(b, &sl); errJSON != nil {
+		helper.ServerLogErr(writer, "incorrect json: %v", ch.logger, 400, errJSON)
+		return
+	}
+
+	stringIds := make([]string, 0, len(sl))
+	for _, id := range sl {
+		stringIds = append(stringIds, id.String())
+	}
+	ch.logger.Infof(helper.APIMessage("[%s] Events %v"), ch.name, stringIds)
+	missing := ch.control(stringIds...)
+	if missing == nil {
+		missing = []string{}
+	}
+
+	output, _ := json.Marshal(missing)
+	if _, errRespond := writer.Write(output); errRespond != nil {
+		ch.logger.Errorf(helper.APIMessage("error responding: %v"), errRespond)
+	}
+}
@@ -81,7 +81,7 @@ func (eh *eventHandler) postput(ctx context.Context, writer http.ResponseWriter,
 	}
 
 	triggerName := params[1]
-	newEvent, _ := eventpreset.CreateEvent(id, eventpreset.REGULAR, triggerName)
+	newEvent, _ := eventpreset.CreateEvent(id, eventpreset.REGULAR, triggerName, eh.baseHandler.namespace)
 
 	errOn := eh.baseHandler.evLoop.RegisterEvent(ctx, newEvent)
 	if errOn != nil {
@@ -15,17 +15,32 @@ const (
 	SUBSCRIBE
 	TOGGLE
 	SCHEDULER
+	PAUSE
+	RESUME
+	CANCEL
 )
 
 // NewHandler создаёт новое событие типа ht, logger и evloop для всех хэндлеров одного сервера должны быть одни и те же
 func NewHandler(ht Type, logger logger.Interface, evLoop eventloop.Interface) http.Handler {
-	bh := baseHandler{logger: logger, evLoop: evLoop}
+	return NewNamespaceHandler(ht, logger, evLoop, "", NewSchedules())
+}
+
+// NewNamespaceHandler создаёт хэндлер типа ht для арендатора namespace. evLoop должен быть менеджером этого
+// пространства имён (eventloop.Interface.Namespace), события из пресетов создаются в нём же. schedules - общие для
+// всех хэндлеров сервера запущенные интервальные события
+func NewNamespaceHandler(
+	ht Type, logger logger.Interface, evLoop eventloop.Interface, namespace string, schedules *Schedules,
+) http.Handler {
+	bh := baseHandler{logger: logger, evLoop: evLoop, namespace: namespace}
 	var handlerMap = map[Type]http.Handler{
 		EVENT:     &eventHandler{bh},
 		TRIGGER:   &triggerHandler{bh},
 		SUBSCRIBE: &subscribeHandler{baseHandler: bh},
 		TOGGLE:    &toggleHandler{bh},
-		SCHEDULER: &schedulerHandler{bh},
+		SCHEDULER: &schedulerHandler{baseHandler: bh, schedules: schedules},
+		PAUSE:     &controlHandler{baseHandler: bh, name: "Pause", control: evLoop.PauseEvent},
+		RESUME:    &controlHandler{baseHandler: bh, name: "Resume", control: evLoop.ResumeEvent},
+		CANCEL:    &controlHandler{baseHandler: bh, name: "Cancel", control: evLoop.CancelEvent},
 	}
 
 	return handlerMap[ht]
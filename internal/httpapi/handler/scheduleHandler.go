@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 	"gitlab.com/YSX/eventloop/internal/httpapi/eventpreset"
@@ -14,9 +15,37 @@ import (
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
 )
 
-// schedulerHandler запускает и останавливает выполнение интервальных событий, создаёт новые из пресетов
+// schedulerHandler создаёт интервальные события из пресетов и запускает их, а запрос без пресета останавливает их
 type schedulerHandler struct {
 	baseHandler
+	schedules *Schedules
+}
+
+// Schedules - запущенные хэндлерами SCHEDULER интервальные события, которые ещё не остановлены, по пространствам
+// имён. Хранятся отдельно от хэндлеров, потому что хэндлеры арендаторов создаются на каждый запрос. Пространство
+// удаляется, когда его события остановлены
+type Schedules struct {
+	mx        sync.Mutex
+	intervals map[string][]event.Interface
+}
+
+func NewSchedules() *Schedules {
+	return &Schedules{intervals: make(map[string][]event.Interface)}
+}
+
+func (s *Schedules) add(namespace string, ev event.Interface) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.intervals[namespace] = append(s.intervals[namespace], ev)
+}
+
+// take забирает события пространства namespace
+func (s *Schedules) take(namespace string) []event.Interface {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	intervals := s.intervals[namespace]
+	delete(s.intervals, namespace)
+	return intervals
 }
 
 // Schedule response model info
@@ -48,8 +77,8 @@ type ScheduleResponse struct {
 func (sh *schedulerHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	var (
 		JSON ScheduleResponse
+		ctx  = request.Context()
 	)
-	ctx, _ := context.WithCancel(context.Background())
 
 	if request.Method != "POST" {
 		helper.NoMethodResponse(writer, "POST")
@@ -59,14 +88,20 @@ func (sh *schedulerHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 
 	param := strings.TrimPrefix(request.URL.Path, "/scheduler/")
 
-	JSON.UUID = sh.scheduleEvent(ctx, writer, &JSON, param)
-
-	if errSS := sh.baseHandler.evLoop.Trigger(ctx, string(eventpreset.INTERVALED)); errSS != nil {
-		writer.WriteHeader(500)
-		JSON.SchedulerStatus = "Event trigger error"
-		sh.baseHandler.logger.Errorf(helper.APIMessage("scheduler start fail: %v"), errSS)
-	} else {
-		JSON.SchedulerStatus = "Scheduler started"
+	if param == "" {
+		JSON.Result = sh.stopIntervals()
+		JSON.SchedulerStatus = "Scheduler stopped"
+	} else if newEvent := sh.scheduleEvent(ctx, writer, &JSON, param); newEvent != nil {
+		// Новое событие запускается отдельно, не переключая уже запущенные интервалы
+		JSON.UUID = uuid.MustParse(newEvent.GetUUID())
+		if errStart := sh.baseHandler.evLoop.StartInterval(newEvent.GetUUID()); errStart != nil {
+			writer.WriteHeader(500)
+			JSON.SchedulerStatus = "Event start error"
+			sh.baseHandler.logger.Errorf(helper.APIMessage("scheduler start fail: %v"), errStart)
+		} else {
+			sh.schedules.add(sh.baseHandler.namespace, newEvent)
+			JSON.SchedulerStatus = "Scheduler started"
+		}
 	}
 
 	byteJSON, _ := json.Marshal(JSON)
@@ -75,23 +110,34 @@ func (sh *schedulerHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 	}
 }
 
+// stopIntervals останавливает запущенные хэндлером интервалы и возвращает значения их последних выполнений. Удалённые
+// за это время события пропускаются
+func (sh *schedulerHandler) stopIntervals() []string {
+	intervals := sh.schedules.take(sh.baseHandler.namespace)
+	result := make([]string, 0, len(intervals))
+	for _, ev := range intervals {
+		if err := sh.baseHandler.evLoop.StopInterval(ev.GetUUID()); err != nil {
+			sh.baseHandler.logger.Debugf(helper.APIMessage("scheduler stop skipped: %v"), err)
+			continue
+		}
+		result = append(result, ev.GetResult())
+	}
+	return result
+}
+
 // scheduleEvent получаем ID ивента из URL, и создаём ивент
 func (sh *schedulerHandler) scheduleEvent(
 	ctx context.Context,
 	writer http.ResponseWriter,
 	jSON *ScheduleResponse,
 	param string,
-) uuid.UUID {
+) event.Interface {
 	var (
 		id       int
 		newEvent event.Interface
 		err      error
 	)
 
-	if param == "" {
-		return uuid.Nil
-	}
-
 	if id, err = strconv.Atoi(param); err != nil {
 		jSON.EventStatus = helper.ServerJSONLogErr(
 			writer,
@@ -101,10 +147,13 @@ func (sh *schedulerHandler) scheduleEvent(
 			param,
 		)
 		sh.logger.Debugf(helper.APIMessage("no event preset requested details: %v"), err)
-		return uuid.Nil
+		return nil
 	}
 
-	if newEvent, err = eventpreset.CreateEvent(id, eventpreset.INTERVALED, string(eventloop.INTERVALED)); err != nil {
+	newEvent, err = eventpreset.CreateEvent(
+		id, eventpreset.INTERVALED, string(eventloop.INTERVALED), sh.baseHandler.namespace,
+	)
+	if err != nil {
 		jSON.EventStatus = helper.ServerJSONLogErr(
 			writer,
 			"error while creating event: %v",
@@ -112,16 +161,16 @@ func (sh *schedulerHandler) scheduleEvent(
 			400,
 			err,
 		)
-		return uuid.Nil
+		return nil
 	}
 
 	if err = sh.baseHandler.evLoop.RegisterEvent(ctx, newEvent); err != nil {
 		writer.WriteHeader(500)
 		jSON.EventStatus = "schedule event fail"
 		sh.baseHandler.logger.Errorf(helper.APIMessage("schedule event fail: %v"), err)
-		return uuid.Nil
+		return nil
 	}
 
 	jSON.EventStatus = "Event is scheduled succesfully"
-	return uuid.MustParse(newEvent.GetUUID())
+	return newEvent
 }
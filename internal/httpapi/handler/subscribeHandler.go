@@ -24,18 +24,32 @@ import (
     ]
 }
 */
+// DELETE отписывает: в JSON того же вида вместо пресетов UUID событий, которые вернул POST
 type subscribeHandler struct {
 	baseHandler
 }
 
-// ServeHTTP принимает JSON с массивами listeners и triggers, в каждом из которых пресеты ивентов, которые будут созданы
-func (sh *subscribeHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	ctx, _ := context.WithCancel(context.Background())
+// SubscribeResponse - UUID созданных событий-триггеров и событий-слушателей, по ним же можно отписаться
+type SubscribeResponse struct {
+	Listeners []string `json:"listeners"`
+	Triggers  []string `json:"triggers"`
+}
 
-	if request.Method != "POST" {
-		helper.NoMethodResponse(writer, "POST")
-		return
+func (sh *subscribeHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case "POST":
+		sh.post(writer, request)
+	case "DELETE":
+		sh.delete(writer, request)
+	default:
+		helper.NoMethodResponse(writer, "POST, DELETE")
 	}
+}
+
+// post принимает JSON с массивами listeners и triggers, в каждом из которых пресеты ивентов, которые будут созданы
+func (sh *subscribeHandler) post(writer http.ResponseWriter, request *http.Request) {
+	// Подписка живёт дольше запроса
+	ctx := context.Background()
 
 	sInfo := struct {
 		Listeners []int `json:"listeners"`
@@ -54,13 +68,17 @@ func (sh *subscribeHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 
 	var (
 		triggers, listeners []event.Interface
+		response            = SubscribeResponse{Listeners: []string{}, Triggers: []string{}}
 	)
 	for _, v := range sInfo.Triggers {
-		newEvent, err := eventpreset.CreateEvent(v, eventpreset.REGULAR, triggerName)
+		newEvent, err := eventpreset.CreateEvent(
+			v, eventpreset.SUBSCRIBE_TRIGGER, triggerName, sh.baseHandler.namespace,
+		)
 		if err != nil {
 			sh.baseHandler.logger.Errorf(helper.APIMessage("Error while creating trigger event: %v"), err)
 		} else {
 			triggers = append(triggers, newEvent)
+			response.Triggers = append(response.Triggers, newEvent.GetUUID())
 			if errRegister := sh.baseHandler.evLoop.RegisterEvent(ctx, newEvent); errRegister != nil {
 				sh.baseHandler.logger.Errorf(helper.APIMessage("schedule event fail: %v"), errRegister)
 			}
@@ -68,16 +86,47 @@ func (sh *subscribeHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 	}
 
 	for _, v := range sInfo.Listeners {
-		newEvent, err := eventpreset.CreateEvent(v, eventpreset.REGULAR, "")
+		newEvent, err := eventpreset.CreateEvent(v, eventpreset.SUBSCRIBE_LISTENER, "", sh.baseHandler.namespace)
 		if err != nil {
 			sh.baseHandler.logger.Errorf(helper.APIMessage("Error while creating listener event: %v"), err)
 		} else {
 			listeners = append(listeners, newEvent)
+			response.Listeners = append(response.Listeners, newEvent.GetUUID())
 		}
 	}
 
 	if errSubscribe := sh.baseHandler.evLoop.Subscribe(ctx, triggers, listeners); errSubscribe != nil {
-		writer.WriteHeader(500)
-		sh.baseHandler.logger.Errorf(helper.APIMessage("event subscribe fail: %v"), errSubscribe)
+		helper.ServerLogErr(writer, "event subscribe fail: %v", sh.logger, 500, errSubscribe)
+		return
+	}
+	sh.respond(writer, response)
+}
+
+// delete godoc
+//
+//	@Summary	Unsubscribe listeners from triggers
+//	@Tags		subscribe
+//	@Accept		json
+//	@Produce	json
+//	@Param		links	body		SubscribeResponse	true	"UUIDs of trigger and listener events to unlink"
+//	@Success	200		{array}		[]string			"UUIDs from request that are not subscribed"
+//	@Failure	400		{string}	string				"Something wrong with request"
+//	@Router		/subscribe/ [delete]
+func (sh *subscribeHandler) delete(writer http.ResponseWriter, request *http.Request) {
+	var links SubscribeResponse
+	if err := json.NewDecoder(request.Body).Decode(&links); err != nil {
+		helper.ServerLogErr(writer, "JSON decode error: %v", sh.logger, 400, err)
+		return
+	}
+	sh.baseHandler.logger.Infof(
+		helper.APIMessage("Unsubscribing listeners %v from triggers %v"), links.Listeners, links.Triggers,
+	)
+	sh.respond(writer, sh.baseHandler.evLoop.Unsubscribe(links.Triggers, links.Listeners))
+}
+
+func (sh *subscribeHandler) respond(writer http.ResponseWriter, response any) {
+	output, _ := json.Marshal(response)
+	if _, errRespond := writer.Write(output); errRespond != nil {
+		sh.baseHandler.logger.Errorf(helper.APIMessage("error responding: %v"), errRespond)
 	}
 }
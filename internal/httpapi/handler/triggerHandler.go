@@ -2,16 +2,21 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"gitlab.com/YSX/eventloop/internal/httpapi/helper"
-	"gitlab.com/YSX/eventloop/pkg/channelEx"
+	"gitlab.com/YSX/eventloop/pkg/eventloop"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
 )
 
-// triggerHandler триггерит ивенты по имени
+// triggerHandler триггерит ивенты по имени. Тело запроса, если оно есть, должно быть JSON - он передаётся событиям
+// как данные триггера
 type triggerHandler struct {
 	baseHandler
 }
@@ -30,23 +35,39 @@ func (th *triggerHandler) ServeHTTP(writer http.ResponseWriter, request *http.Re
 		return
 	}
 
-	ch := channelEx.NewChannel(1)
+	payload, errPayload := readPayload(request)
+	if errPayload != nil {
+		helper.ServerLogErr(writer, "JSON decode error: %v", th.logger, 400, errPayload)
+		return
+	}
 
-	var errTrig error
-	go func() {
-		if errTrig = th.baseHandler.evLoop.Trigger(triggerCtx, param); errTrig != nil {
-			io.WriteString(writer, "Event trigger fail")
-			th.baseHandler.logger.Errorf(helper.APIMessage("event trigger fail: %v"), errTrig)
-			return
-		}
-	}()
+	result, errTrig := th.baseHandler.evLoop.TriggerWithPayload(triggerCtx, param, payload)
+	var rateErr *eventloop.RateLimitError
+	if errors.As(errTrig, &rateErr) {
+		// Retry-After - целые секунды, округляем вверх, чтобы повтор не пришёл раньше времени
+		retryAfter := (rateErr.RetryAfter + time.Second - 1) / time.Second
+		writer.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+		helper.ServerLogErr(writer, "Event trigger rate limited: %v", th.logger, 429, errTrig)
+		return
+	}
+	if errors.Is(errTrig, event.ErrReservedTriggerName) {
+		helper.ServerLogErr(writer, "Invalid trigger name: %v", th.logger, 400, errTrig)
+		return
+	}
+	if errTrig != nil {
+		helper.ServerLogErr(writer, "Event trigger fail: %v", th.logger, 500, errTrig)
+		return
+	}
 
-	var (
-		output []string
-	)
-	for elem := range ch.Channel() {
-		output = append(output, elem)
+	if result.Suppressed {
+		writer.WriteHeader(202)
+		if _, err := io.WriteString(writer, "suppressed by "+string(result.SuppressedBy)); err != nil {
+			th.logger.Errorf(helper.APIMessage("error while sending trigger results: %v"), err)
+		}
+		return
 	}
+
+	output := result.Values()
 	if len(output) == 0 {
 		helper.ServerLogErr(writer, "nothing to trigger", th.logger, 204)
 		return
@@ -58,3 +79,13 @@ func (th *triggerHandler) ServeHTTP(writer http.ResponseWriter, request *http.Re
 		helper.ServerLogErr(writer, "error while sending trigger results: %v", th.logger, 500, err)
 	}
 }
+
+// readPayload читает JSON из тела запроса. Пустое тело - это nil, а не ошибка
+func readPayload(request *http.Request) (payload any, err error) {
+	b, err := io.ReadAll(request.Body)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &payload)
+	return payload, err
+}
@@ -106,6 +106,19 @@ func removeEvent(t *testing.T, ids []string) string {
 	return result
 }
 
+// controlEvents ставит события на паузу, снимает с паузы или отменяет: action - pause, resume или cancel
+func controlEvents(t *testing.T, action string, ids []string) string {
+	encodedJson, errJson := json.Marshal(ids)
+	if errJson != nil {
+		t.Errorf("can't marshal json: %v", errJson)
+		return ""
+	}
+	resp, err := http.Post(
+		fmt.Sprintf("http://localhost:8090/%v/", action), "application/json", bytes.NewReader(encodedJson),
+	)
+	return handleRequest(t, resp, err)
+}
+
 func subscribeEvents(
 	t *testing.T, eventName string, inputJSON struct {
 		Listeners []int `json:"listeners"`
@@ -128,20 +141,35 @@ func subscribeEvents(
 	return result
 }
 
-func scheduleEvent(t *testing.T, body *bytes.Buffer) (*http.Response, string) {
-	resp, err := http.Post("http://localhost:8090/scheduler/1", "text/plain", body)
+// scheduleEvent и scheduleStop обращаются к планировщику по пути с префиксом prefix ("" или /tenants/{tenant})
+func scheduleEvent(t *testing.T, prefix string, body *bytes.Buffer) (*http.Response, string) {
+	resp, err := http.Post("http://localhost:8090"+prefix+"/scheduler/1", "text/plain", body)
 	return resp, handleRequest(t, resp, err)
 }
 
-func scheduleStop(t *testing.T) (resp *http.Response, JSON handler.ScheduleResponse) {
+func scheduleStop(t *testing.T, prefix string) (resp *http.Response, JSON handler.ScheduleResponse) {
 	var (
 		err error
 	)
 
 	data := bytes.NewBufferString("STOP")
-	resp, err = http.Post("http://localhost:8090/scheduler/", "text/plain", data)
+	resp, err = http.Post("http://localhost:8090"+prefix+"/scheduler/", "text/plain", data)
 
 	JSON = handleJsonRequest[handler.ScheduleResponse](t, resp, err)
 
 	return
 }
+
+// unsubscribeEvents отписывает слушателей от триггеров по UUID и возвращает UUID из запроса, которые не подписаны
+func unsubscribeEvents(t *testing.T, links handler.SubscribeResponse) []string {
+	encodedJson, errJson := json.Marshal(links)
+	if errJson != nil {
+		t.Fatalf("can't marshal json: %v", errJson)
+	}
+	req, err := http.NewRequest("DELETE", "http://localhost:8090/subscribe/", bytes.NewReader(encodedJson))
+	if err != nil {
+		t.Fatalf("error creating request: %v", err)
+	}
+	resp, errDo := http.DefaultClient.Do(req)
+	return handleJsonRequest[[]string](t, resp, errDo)
+}
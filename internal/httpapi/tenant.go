@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"gitlab.com/YSX/eventloop/internal/httpapi/handler"
+	"gitlab.com/YSX/eventloop/internal/httpapi/helper"
+	"gitlab.com/YSX/eventloop/pkg/eventloop"
+	"gitlab.com/YSX/eventloop/pkg/logger"
+)
+
+const (
+	// TenantHeader - заголовок с арендатором, запросы с ним уходят в его пространство имён
+	TenantHeader = "X-Tenant"
+	// tenantPathPrefix - то же через путь: /tenants/{tenant}/trigger/{triggerName}
+	tenantPathPrefix = "/tenants/"
+)
+
+// tenantRouter отправляет запросы арендаторов (заголовок TenantHeader или путь tenantPathPrefix) в маршруты их
+// пространств имён, а остальные - в общие маршруты root
+type tenantRouter struct {
+	root      http.Handler
+	logger    logger.Interface
+	evLoop    eventloop.Interface
+	schedules *handler.Schedules
+}
+
+func newTenantRouter(
+	root http.Handler, logger logger.Interface, evLoop eventloop.Interface, schedules *handler.Schedules,
+) *tenantRouter {
+	return &tenantRouter{root: root, logger: logger, evLoop: evLoop, schedules: schedules}
+}
+
+func (tr *tenantRouter) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	tenant := request.Header.Get(TenantHeader)
+	path := request.URL.Path
+	if strings.HasPrefix(path, tenantPathPrefix) {
+		pathTenant, tenantPath, _ := strings.Cut(strings.TrimPrefix(path, tenantPathPrefix), "/")
+		if tenant != "" && tenant != pathTenant {
+			helper.ServerLogErr(writer, "Tenant %q in path differs from %v header %q", tr.logger, 400, pathTenant,
+				TenantHeader, tenant)
+			return
+		}
+		tenant, path = pathTenant, "/"+tenantPath
+	}
+	if tenant == "" {
+		tr.root.ServeHTTP(writer, request)
+		return
+	}
+
+	mux, err := tr.tenant(tenant)
+	if err != nil {
+		helper.ServerLogErr(writer, "Invalid tenant: %v", tr.logger, 400, err)
+		return
+	}
+	tenantRequest := request.Clone(request.Context())
+	tenantRequest.URL.Path = path
+	mux.ServeHTTP(writer, tenantRequest)
+}
+
+// tenant возвращает маршруты пространства имён арендатора. Они создаются на каждый запрос и не кэшируются, чтобы
+// запросы с произвольными именами арендаторов не копили маршруты в памяти сервера
+func (tr *tenantRouter) tenant(tenant string) (http.Handler, error) {
+	tenantLoop, err := tr.evLoop.Namespace(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return newMux(tr.logger, tenantLoop, tenant, tr.schedules), nil
+}
@@ -0,0 +1,58 @@
+package clock
+
+import "time"
+
+// component - настоящее время, обёртка над пакетом time
+type component struct{}
+
+func New() Interface {
+	return component{}
+}
+
+func (component) Now() time.Time {
+	return time.Now()
+}
+
+func (component) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (component) NewTimer(d time.Duration) Timer {
+	return &timer{t: time.NewTimer(d)}
+}
+
+func (component) NewTicker(d time.Duration) Ticker {
+	return &ticker{t: time.NewTicker(d)}
+}
+
+func (component) AfterFunc(d time.Duration, f func()) Timer {
+	return &timer{t: time.AfterFunc(d, f)}
+}
+
+type timer struct {
+	t *time.Timer
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t *timer) Stop() bool {
+	return t.t.Stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	return t.t.Reset(d)
+}
+
+type ticker struct {
+	t *time.Ticker
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t *ticker) Stop() {
+	t.t.Stop()
+}
@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_component_Now(t *testing.T) {
+	before := time.Now()
+	got := New().Now()
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("Now() = %v, want between %v and now", got, before)
+	}
+}
+
+func Test_component_NewTimer(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		stop     bool
+		wantFire bool
+	}{
+		{
+			name:     "Fire",
+			duration: time.Millisecond,
+			wantFire: true,
+		},
+		{
+			name:     "Stop",
+			duration: 50 * time.Millisecond,
+			stop:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := New().NewTimer(tt.duration)
+			if tt.stop {
+				timer.Stop()
+			}
+			select {
+			case <-timer.C():
+				if !tt.wantFire {
+					t.Errorf("timer fired after Stop()")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantFire {
+					t.Errorf("timer did not fire")
+				}
+			}
+		})
+	}
+}
+
+func Test_component_AfterFunc(t *testing.T) {
+	called := make(chan struct{})
+	New().AfterFunc(time.Millisecond, func() { close(called) })
+	select {
+	case <-called:
+	case <-time.After(100 * time.Millisecond):
+		t.Error("AfterFunc() did not call f")
+	}
+}
+
+func Test_component_NewTicker(t *testing.T) {
+	ticker := New().NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ticker.C():
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("tick %v did not come", i)
+		}
+	}
+}
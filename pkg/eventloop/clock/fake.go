@@ -0,0 +1,209 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake - часы для тестов. Время стоит на месте, пока его не сдвинут Advance или Set. Таймеры и тикеры срабатывают,
+// когда до них дошло время, поэтому тесты с интервалами и задержками выполняются мгновенно и всегда одинаково
+type Fake struct {
+	mx      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	// changed закрывается при каждом новом таймере или тикере, чтобы BlockUntil проверил их число заново
+	changed chan struct{}
+	// due - сработавшие функции AfterFunc, которые unlock вызовет после f.mx
+	due []func()
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, changed: make(chan struct{})}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// NewTimer создаёт таймер, который сработает, когда часы дойдут до Now() + d. При d <= 0 срабатывает сразу
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	w := &fakeWaiter{f: f, c: make(chan time.Time, 1)}
+	f.mx.Lock()
+	defer f.unlock()
+	w.start(d)
+	return &fakeTimer{w: w}
+}
+
+// NewTicker создаёт тикер с периодом d. Как и time.NewTicker, паникует при d <= 0
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	w := &fakeWaiter{f: f, c: make(chan time.Time, 1), period: d}
+	f.mx.Lock()
+	defer f.unlock()
+	w.start(d)
+	return &fakeTicker{w: w}
+}
+
+// AfterFunc вызывает f, когда часы дойдут до Now() + d. f выполняется в горутине, которая сдвинула часы (Advance, Set),
+// а при d <= 0 - сразу в горутине AfterFunc, поэтому тесты видят результат f сразу после сдвига
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	w := &fakeWaiter{f: f, fn: fn}
+	f.mx.Lock()
+	w.start(d)
+	f.unlock()
+	return &fakeTimer{w: w}
+}
+
+// Advance сдвигает время вперёд на d и срабатывают все таймеры и тикеры, до которых дошло время. За один Advance
+// тикер срабатывает не больше одного раза - как настоящий тикер, тики которого никто не успел прочитать
+func (f *Fake) Advance(d time.Duration) {
+	f.mx.Lock()
+	defer f.unlock()
+	f.now = f.now.Add(d)
+	f.fire()
+}
+
+// Set переводит часы на t. Если t в будущем, срабатывает всё, до чего дошло время, как при Advance
+func (f *Fake) Set(t time.Time) {
+	f.mx.Lock()
+	defer f.unlock()
+	f.now = t
+	f.fire()
+}
+
+// unlock отпускает f.mx и вызывает функции AfterFunc, до которых дошло время. Функции вызываются без блокировки, чтобы
+// они могли сами заводить таймеры
+func (f *Fake) unlock() {
+	due := f.due
+	f.due = nil
+	f.mx.Unlock()
+	for _, fn := range due {
+		fn()
+	}
+}
+
+// BlockUntil ждёт, пока у часов не станет хотя бы n активных таймеров и тикеров. Так тест узнаёт, что горутина под
+// тестом дошла до ожидания и часы можно двигать
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mx.Lock()
+		if len(f.waiters) >= n {
+			f.mx.Unlock()
+			return
+		}
+		changed := f.changed
+		f.mx.Unlock()
+		<-changed
+	}
+}
+
+// fire отправляет время во все таймеры и тикеры, до которых оно дошло, в порядке их срабатывания. Вызывается под f.mx
+func (f *Fake) fire() {
+	due := make([]*fakeWaiter, 0, len(f.waiters))
+	for _, w := range f.waiters {
+		if !w.when.After(f.now) {
+			due = append(due, w)
+		}
+	}
+	sort.SliceStable(
+		due, func(i, j int) bool {
+			return due[i].when.Before(due[j].when)
+		},
+	)
+
+	for _, w := range due {
+		if w.fn != nil {
+			f.due = append(f.due, w.fn)
+		}
+		select {
+		case w.c <- w.when:
+		default:
+		}
+		if w.period == 0 {
+			f.remove(w)
+			continue
+		}
+		// Тикер ждёт первый тик после текущего времени
+		w.when = w.when.Add((f.now.Sub(w.when)/w.period + 1) * w.period)
+	}
+}
+
+func (f *Fake) add(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// remove убирает таймер или тикер из активных. false - его там и не было
+func (f *Fake) remove(w *fakeWaiter) bool {
+	for i, waiter := range f.waiters {
+		if waiter == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeWaiter - общая часть таймера и тикера. period == 0 - таймер, fn != nil - таймер AfterFunc без канала
+type fakeWaiter struct {
+	f      *Fake
+	c      chan time.Time
+	fn     func()
+	when   time.Time
+	period time.Duration
+}
+
+// start ставит таймер или тикер на срабатывание через d. Вызывается под f.mx
+func (w *fakeWaiter) start(d time.Duration) bool {
+	active := w.f.remove(w)
+	w.when = w.f.now.Add(d)
+	w.f.add(w)
+	w.f.fire()
+	return active
+}
+
+func (w *fakeWaiter) stop() bool {
+	w.f.mx.Lock()
+	defer w.f.mx.Unlock()
+	return w.f.remove(w)
+}
+
+type fakeTimer struct {
+	w *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.w.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.w.stop()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.w.f.mx.Lock()
+	defer t.w.f.unlock()
+	return t.w.start(d)
+}
+
+type fakeTicker struct {
+	w *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.w.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.w.stop()
+}
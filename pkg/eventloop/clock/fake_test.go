@@ -0,0 +1,169 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var fakeStart = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// fired - сработал ли канал таймера или тикера к этому моменту
+func fired(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFake_Advance(t *testing.T) {
+	f := NewFake(fakeStart)
+	f.Advance(time.Minute)
+	if got, want := f.Now(), fakeStart.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+	if got := f.Since(fakeStart); got != time.Minute {
+		t.Errorf("Since() = %v, want %v", got, time.Minute)
+	}
+}
+
+func TestFake_NewTimer(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		advance  time.Duration
+		stop     bool
+		reset    time.Duration
+		wantFire bool
+	}{
+		{
+			name:     "Fire",
+			duration: time.Minute,
+			advance:  time.Minute,
+			wantFire: true,
+		},
+		{
+			name:     "Not yet",
+			duration: time.Minute,
+			advance:  time.Second,
+		},
+		{
+			name:     "Immediately",
+			duration: 0,
+			wantFire: true,
+		},
+		{
+			name:     "Stop",
+			duration: time.Minute,
+			advance:  time.Minute,
+			stop:     true,
+		},
+		{
+			name:     "Reset",
+			duration: time.Second,
+			advance:  time.Second,
+			reset:    time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFake(fakeStart)
+			timer := f.NewTimer(tt.duration)
+			if tt.stop && !timer.Stop() {
+				t.Errorf("Stop() = false, want true for active timer")
+			}
+			if tt.reset != 0 && !timer.Reset(tt.reset) {
+				t.Errorf("Reset() = false, want true for active timer")
+			}
+			f.Advance(tt.advance)
+
+			got, ok := fired(timer.C())
+			if ok != tt.wantFire {
+				t.Fatalf("timer fired = %v, want %v", ok, tt.wantFire)
+			}
+			if want := fakeStart.Add(tt.duration); ok && !got.Equal(want) {
+				t.Errorf("timer fired at %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFake_AfterFunc(t *testing.T) {
+	var (
+		f     = NewFake(fakeStart)
+		calls int
+		timer = f.AfterFunc(
+			time.Minute, func() {
+				calls++
+				// Функция вызывается без блокировки часов
+				f.Now()
+			},
+		)
+	)
+	if timer.C() != nil {
+		t.Error("AfterFunc() timer has a channel")
+	}
+	f.Advance(time.Second)
+	if calls != 0 {
+		t.Fatalf("AfterFunc() called before time")
+	}
+	f.Advance(time.Minute)
+	if calls != 1 {
+		t.Fatalf("AfterFunc() calls = %v after Advance(), want 1", calls)
+	}
+	if timer.Reset(time.Minute) {
+		t.Error("Reset() of fired timer = true, want false")
+	}
+	timer.Stop()
+	f.Advance(time.Hour)
+	if calls != 1 {
+		t.Errorf("AfterFunc() calls = %v after Stop(), want 1", calls)
+	}
+}
+
+func TestFake_NewTicker(t *testing.T) {
+	f := NewFake(fakeStart)
+	ticker := f.NewTicker(time.Minute)
+
+	f.Advance(time.Minute)
+	if got, ok := fired(ticker.C()); !ok || !got.Equal(fakeStart.Add(time.Minute)) {
+		t.Errorf("first tick = %v, %v, want %v", got, ok, fakeStart.Add(time.Minute))
+	}
+
+	// Пропущенные тики не копятся
+	f.Advance(3 * time.Minute)
+	if got, ok := fired(ticker.C()); !ok || !got.Equal(fakeStart.Add(2*time.Minute)) {
+		t.Errorf("late tick = %v, %v, want %v", got, ok, fakeStart.Add(2*time.Minute))
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Errorf("missed ticks were queued")
+	}
+
+	f.Advance(time.Minute)
+	if got, ok := fired(ticker.C()); !ok || !got.Equal(fakeStart.Add(5*time.Minute)) {
+		t.Errorf("tick after catch-up = %v, %v, want %v", got, ok, fakeStart.Add(5*time.Minute))
+	}
+
+	ticker.Stop()
+	f.Advance(time.Minute)
+	if _, ok := fired(ticker.C()); ok {
+		t.Errorf("ticker ticked after Stop()")
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	var (
+		f    = NewFake(fakeStart)
+		done = make(chan time.Time)
+	)
+	go func() {
+		done <- <-f.NewTimer(time.Hour).C()
+	}()
+
+	f.BlockUntil(1)
+	f.Advance(time.Hour)
+	if got := <-done; !got.Equal(fakeStart.Add(time.Hour)) {
+		t.Errorf("timer fired at %v, want %v", got, fakeStart.Add(time.Hour))
+	}
+}
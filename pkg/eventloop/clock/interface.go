@@ -0,0 +1,24 @@
+package clock
+
+import "time"
+
+// Interface - источник времени для менеджера событий. Позволяет подменить время в тестах
+type Interface interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	// AfterFunc вызывает f через d. Как у time.AfterFunc, C() у таймера nil, Stop отменяет вызов, а Reset - переносит
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
@@ -0,0 +1,185 @@
+package eventloop
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/cron"
+	loggerEventLoop "gitlab.com/YSX/eventloop/pkg/logger"
+	"golang.org/x/exp/maps"
+)
+
+// startCronEvent запускает cron событие при регистрации. Событие живёт, пока его не удалят или не остановят менеджер
+// событий: тогда отменяется и контекст его запусков
+func (e *eventLoop) startCronEvent(ev event.Interface) {
+	cronComponent, err := ev.Cron()
+	if err != nil {
+		return
+	}
+	e.intervalMx.Lock()
+	defer e.intervalMx.Unlock()
+	// Shutdown останавливает cron события под intervalMx, после него новые не запускаются
+	if cronComponent.IsRunning() || e.isShutdown() {
+		return
+	}
+
+	run := &cronRun{e: e, ev: ev, component: cronComponent}
+	run.ctx, run.cancel = context.WithCancel(loggerEventLoop.WithLogger(context.Background(), e.logger))
+	e.logger.Infow(
+		"Cron event starting",
+		"eventId", ev.GetUUID(), "expression", cronComponent.Expression(), "location", cronComponent.Location(),
+	)
+
+	run.mx.Lock()
+	defer run.mx.Unlock()
+	if !run.schedule(e.clock.Now()) {
+		run.cancel()
+		e.logger.Warnw("Cron event has no next run, not starting", "eventId", ev.GetUUID())
+		return
+	}
+	cronComponent.SetRunning(true)
+	if e.crons == nil {
+		e.crons = make(map[string]*cronRun)
+	}
+	e.crons[ev.GetUUID()] = run
+}
+
+// stopCronEvents останавливает cron события, которые удаляются из менеджера
+func (e *eventLoop) stopCronEvents(uUIDs ...string) {
+	var runs []*cronRun
+	e.intervalMx.Lock()
+	for _, uUID := range uUIDs {
+		if run := e.crons[uUID]; run != nil {
+			runs = append(runs, run)
+		}
+	}
+	e.intervalMx.Unlock()
+	for _, run := range runs {
+		e.endCron(run)
+	}
+}
+
+// stopCrons останавливает все запущенные cron события. Нужна при Shutdown
+func (e *eventLoop) stopCrons() {
+	e.intervalMx.Lock()
+	runs := maps.Values(e.crons)
+	e.intervalMx.Unlock()
+	for _, run := range runs {
+		e.endCron(run)
+	}
+}
+
+// endCron снимает таймер cron события и отмечает его остановленным. false - run уже остановлен
+func (e *eventLoop) endCron(run *cronRun) bool {
+	e.intervalMx.Lock()
+	defer e.intervalMx.Unlock()
+	if !run.stop() {
+		return false
+	}
+	delete(e.crons, run.ev.GetUUID())
+	run.component.SetRunning(false)
+	e.logger.Infow("Cron event stopped", "eventId", run.ev.GetUUID())
+	return true
+}
+
+// cronRun - запущенное cron событие. Как и у интервала, своей горутины у него нет: срабатывания приходят функцией
+// AfterFunc планировщика, а функция события выполняется через spawn
+type cronRun struct {
+	e         *eventLoop
+	ev        event.Interface
+	component cron.Interface
+	// ctx - контекст запусков функции, отменяется при остановке события
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mx      sync.Mutex
+	timer   clock.Timer
+	stopped bool
+	// next - время ближайшего запуска по расписанию
+	next time.Time
+}
+
+// schedule заводит таймер на ближайший после now запуск по расписанию. false - запусков больше нет. Вызывается под
+// r.mx
+func (r *cronRun) schedule(now time.Time) bool {
+	r.next = r.component.Next(now)
+	if r.next.IsZero() {
+		return false
+	}
+	r.e.logger.Debugw("Cron event waiting", "eventId", r.ev.GetUUID(), "next", r.next)
+	if r.timer == nil {
+		r.timer = r.e.timers().AfterFunc(r.next.Sub(now), r.fire)
+	} else {
+		r.timer.Reset(r.next.Sub(now))
+	}
+	return true
+}
+
+// stop снимает таймер и отменяет контекст запусков. false - событие уже остановлено
+func (r *cronRun) stop() bool {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if r.stopped {
+		return false
+	}
+	r.stopped = true
+	r.timer.Stop()
+	r.cancel()
+	return true
+}
+
+// fire - срабатывание таймера cron события в горутине планировщика. Переставляет таймер на следующий запуск и отдаёт
+// функцию события в spawn. Событие, у которого запусков больше нет, останавливается после этого
+func (r *cronRun) fire() {
+	e, ev := r.e, r.ev
+	r.mx.Lock()
+	if r.stopped {
+		r.mx.Unlock()
+		return
+	}
+	now := e.clock.Now()
+	runs := missedRuns(ev.GetMisfire(), r.next, now, r.component.Next)
+	lateBy := now.Sub(r.next)
+	last := !r.schedule(now)
+	r.mx.Unlock()
+
+	finish := func() {
+		if last {
+			e.logger.Warnw("Cron event has no next run, stopping", "eventId", ev.GetUUID())
+			e.endCron(r)
+		}
+	}
+	if len(runs) == 0 {
+		e.handleResult(r.ctx, e.missedResult(ev, lateBy))
+		finish()
+		return
+	}
+	if ev.IsPaused() {
+		e.handleResult(r.ctx, e.pausedResult(ev))
+		finish()
+		return
+	}
+	e.spawn(
+		r.ctx, func(ctx context.Context) (result event.Result) {
+			defer finish()
+			for _, lateBy := range runs {
+				result = e.handleResult(ctx, e.runLate(ctx, ev, lateBy))
+				if once, onceErr := ev.Once(); onceErr == nil {
+					once.Do(
+						func() {
+							e.removeEvents(ev.GetUUID())
+						},
+					)
+					return result
+				}
+			}
+			return result
+		}, func(err error) {
+			defer finish()
+			e.handleResult(r.ctx, e.notExecutedResult(ev, err))
+		},
+	)
+}
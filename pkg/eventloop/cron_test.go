@@ -0,0 +1,159 @@
+package eventloop
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/cron"
+)
+
+func Test_eventLoop_startCronEvent(t *testing.T) {
+	var start = time.Date(2023, 3, 10, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		isOnce   bool
+		wantRuns []time.Time
+	}{
+		{
+			name:     "Runs by schedule",
+			wantRuns: []time.Time{start.Add(30 * time.Minute), start.Add(90 * time.Minute)},
+		},
+		{
+			name:     "Once",
+			isOnce:   true,
+			wantRuns: []time.Time{start.Add(30 * time.Minute)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					fake  = clock.NewFake(start)
+					runs  = make(chan time.Time, len(tt.wantRuns))
+					e     = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+					ev, _ = event.NewEvent(
+						event.Args{
+							Cron:   cron.Args{Expression: "@hourly"},
+							IsOnce: tt.isOnce,
+							Fun: func(ctx context.Context) string {
+								runs <- fake.Now()
+								return "OK"
+							},
+						},
+					)
+				)
+				if err := e.RegisterEvent(context.Background(), ev); err != nil {
+					t.Fatal(err)
+				}
+				defer e.RemoveEventByUUIDs(ev.GetUUID())
+
+				for _, want := range tt.wantRuns {
+					fake.BlockUntil(1)
+					fake.Set(want)
+					if got := <-runs; !got.Equal(want) {
+						t.Errorf("cron event ran at %v, want %v", got, want)
+					}
+				}
+
+				cronComponent, _ := ev.Cron()
+				if tt.isOnce {
+					for deadline := time.Now().Add(time.Second); cronComponent.IsRunning(); {
+						if time.Now().After(deadline) {
+							t.Fatal("once cron event is still running after the first run")
+						}
+						time.Sleep(time.Millisecond)
+					}
+					if got, _ := e.(*eventLoop).GetEventsByType("CRON"); len(got) != 0 {
+						t.Errorf("once cron event is not removed: %v", got)
+					}
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_RegisterEvent_Cron(t *testing.T) {
+	var (
+		e     = NewEventLoop(WithLogger(newTestLogger()))
+		ev, _ = event.NewEvent(
+			event.Args{
+				Cron: cron.Args{Expression: "0 0 1 1 *"}, Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	if err := e.RegisterEvent(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	cronComponent, _ := ev.Cron()
+	if !cronComponent.IsRunning() {
+		t.Errorf("cron event is not running after RegisterEvent()")
+	}
+	report, _ := e.Shutdown(context.Background())
+	if len(report.StoppedCrons) != 1 || report.StoppedCrons[0] != ev.GetUUID() {
+		t.Errorf("Shutdown() stopped crons = %v, want [%v]", report.StoppedCrons, ev.GetUUID())
+	}
+}
+
+func Test_eventLoop_startCronEvent_Goroutines(t *testing.T) {
+	const events = 100
+	var (
+		e      = NewEventLoop(WithLogger(newTestLogger()))
+		before = runtime.NumGoroutine()
+	)
+	for i := 0; i < events; i++ {
+		ev, _ := event.NewEvent(
+			event.Args{
+				Cron: cron.Args{Expression: "0 0 1 1 *"}, Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+		if err := e.RegisterEvent(context.Background(), ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Сроки всех cron событий лежат в куче планировщика, горутина на событие не заводится
+	if grown := runtime.NumGoroutine() - before; grown > events/10 {
+		t.Errorf("goroutines grown by %v for %v cron events", grown, events)
+	}
+	if report, _ := e.Shutdown(context.Background()); len(report.StoppedCrons) != events {
+		t.Errorf("Shutdown() stopped %v crons, want %v", len(report.StoppedCrons), events)
+	}
+}
+
+func Test_eventLoop_Shutdown_CancelsCronRun(t *testing.T) {
+	var (
+		fake    = clock.NewFake(time.Date(2023, 3, 10, 12, 30, 0, 0, time.UTC))
+		e       = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		started = make(chan struct{})
+		ev, _   = event.NewEvent(
+			event.Args{
+				Cron: cron.Args{Expression: "@hourly"}, Fun: func(ctx context.Context) string {
+					close(started)
+					<-ctx.Done()
+					return "CANCELLED"
+				},
+			},
+		)
+	)
+	if err := e.RegisterEvent(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	fake.BlockUntil(1)
+	fake.Advance(30 * time.Minute)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if report, err := e.Shutdown(ctx); err != nil || len(report.Aborted) != 0 {
+		t.Errorf("Shutdown() = %+v, %v, want cron run cancelled and awaited", report, err)
+	}
+}
@@ -0,0 +1,144 @@
+package eventloop
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DebounceEdge - на каком краю серии вызовов триггера выполняются его события
+type DebounceEdge string
+
+const (
+	// DebounceTrailing - события выполняет последний вызов серии, когда после него прошло Debounce.Wait без новых
+	// вызовов. Вызов ждёт этого, а все предыдущие вызовы серии подавляются (по умолчанию)
+	DebounceTrailing DebounceEdge = "TRAILING"
+	// DebounceLeading - события выполняет первый вызов серии, остальные вызовы серии подавляются сразу
+	DebounceLeading DebounceEdge = "LEADING"
+	// DebounceBoth - события выполняют первый и последний вызовы серии
+	DebounceBoth DebounceEdge = "BOTH"
+)
+
+// SuppressReason - почему вызов триггера не выполнял события
+type SuppressReason string
+
+const (
+	SuppressedByDebounce SuppressReason = "DEBOUNCE"
+	SuppressedByThrottle SuppressReason = "THROTTLE"
+)
+
+// Debounce - выполнять события триггера один раз на серию вызовов. Серия заканчивается, когда между вызовами прошло
+// Wait. Wait == 0 - debounce выключен
+type Debounce struct {
+	Wait time.Duration
+	Edge DebounceEdge
+	// MaxWait - дольше этого события не откладываются: при непрерывной серии вызовов они выполняются хотя бы раз в
+	// MaxWait. 0 - без ограничения
+	MaxWait time.Duration
+}
+
+func (d Debounce) validate() (Debounce, error) {
+	if d.Wait == 0 {
+		return d, nil
+	}
+	if d.Edge == "" {
+		d.Edge = DebounceTrailing
+	}
+	switch {
+	case d.Wait < 0:
+		return d, fmt.Errorf("negative debounce wait %v", d.Wait)
+	case d.MaxWait != 0 && d.MaxWait < d.Wait:
+		return d, fmt.Errorf("debounce max wait %v is less than wait %v", d.MaxWait, d.Wait)
+	}
+	switch d.Edge {
+	case DebounceTrailing, DebounceLeading, DebounceBoth:
+		return d, nil
+	default:
+		return d, fmt.Errorf("unknown debounce edge %q", d.Edge)
+	}
+}
+
+// debouncer - состояние debounce одного триггера
+type debouncer struct {
+	config Debounce
+
+	mx sync.Mutex
+	// last - время последнего вызова, по нему видно, что серия закончилась
+	last time.Time
+	// since - начало серии или последнее выполнение в ней, от него отсчитывается MaxWait
+	since time.Time
+	// pending закрывается, когда ждущий вызов вытесняет более поздний вызов серии. nil - никто не ждёт
+	pending chan struct{}
+	// suppressed - сколько вызовов подавлено с последнего выполнения
+	suppressed int
+}
+
+// debounce решает, выполняет ли вызов триггера события. Вызов на заднем краю серии ждёт, пока серия закончится.
+// Возвращает, сколько вызовов было подавлено до этого выполнения, либо suppressed == true, если этот вызов подавлен
+func (e *eventLoop) debounce(ctx context.Context, d *debouncer) (suppressedCalls int, suppressed bool, err error) {
+	now := e.clock.Now()
+	d.mx.Lock()
+	quiet := d.last.IsZero() || now.Sub(d.last) >= d.config.Wait
+	d.last = now
+	if quiet {
+		d.since = now
+	}
+
+	leading := d.config.Edge != DebounceTrailing && quiet
+	// Только передний край: MaxWait выполняет события посреди серии
+	if d.config.Edge == DebounceLeading && d.config.MaxWait > 0 && now.Sub(d.since) >= d.config.MaxWait {
+		leading = true
+		d.since = now
+	}
+	if leading {
+		suppressedCalls, d.suppressed = d.suppressed, 0
+		d.mx.Unlock()
+		return suppressedCalls, false, nil
+	}
+	if d.config.Edge == DebounceLeading {
+		d.suppressed++
+		d.mx.Unlock()
+		return 0, true, nil
+	}
+
+	// Задний край: этот вызов вытесняет ждущий и ждёт сам
+	if d.pending != nil {
+		close(d.pending)
+		d.suppressed++
+	}
+	pending := make(chan struct{})
+	d.pending = pending
+	deadline := now.Add(d.config.Wait)
+	if maxDeadline := d.since.Add(d.config.MaxWait); d.config.MaxWait > 0 && maxDeadline.Before(deadline) {
+		deadline = maxDeadline
+	}
+	d.mx.Unlock()
+
+	timer := e.timers().NewTimer(deadline.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-pending:
+		return 0, true, nil
+	case <-timer.C():
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-e.done:
+		err = ErrShutdown
+	}
+
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	if d.pending != pending {
+		// Вытеснили, пока ждали
+		return 0, true, nil
+	}
+	d.pending = nil
+	if err != nil {
+		d.suppressed++
+		return 0, false, err
+	}
+	d.since = e.clock.Now()
+	suppressedCalls, d.suppressed = d.suppressed, 0
+	return suppressedCalls, false, nil
+}
@@ -0,0 +1,223 @@
+package eventloop
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+func TestDebounce_validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		debounce Debounce
+		want     Debounce
+		wantErr  bool
+	}{
+		{
+			name: "Off",
+		},
+		{
+			name:     "Default edge",
+			debounce: Debounce{Wait: time.Second},
+			want:     Debounce{Wait: time.Second, Edge: DebounceTrailing},
+		},
+		{
+			name:     "Negative wait",
+			debounce: Debounce{Wait: -time.Second},
+			wantErr:  true,
+		},
+		{
+			name:     "Max wait less than wait",
+			debounce: Debounce{Wait: time.Second, MaxWait: time.Millisecond},
+			wantErr:  true,
+		},
+		{
+			name:     "Unknown edge",
+			debounce: Debounce{Wait: time.Second, Edge: "MIDDLE"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := tt.debounce.validate()
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && got != tt.want {
+					t.Errorf("validate() = %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_Trigger_Debounce(t *testing.T) {
+	// step - вызов триггера через after после предыдущего шага. wait - вызов ждёт конца серии, его результат
+	// проверяется после следующего шага, на котором его вытеснят или часы дойдут до конца серии
+	type step struct {
+		after time.Duration
+		wait  bool
+		want  TriggerResult
+	}
+	var (
+		ran        = TriggerResult{TriggerName: "Debounced"}
+		suppressed = TriggerResult{TriggerName: "Debounced", Suppressed: true, SuppressedBy: SuppressedByDebounce}
+	)
+	tests := []struct {
+		name     string
+		debounce Debounce
+		steps    []step
+		// end - на сколько сдвинуть часы после всех шагов, чтобы дождаться последнего вызова
+		end time.Duration
+		// wantLast - результат последнего вызова
+		wantLast TriggerResult
+	}{
+		{
+			name:     "Trailing",
+			debounce: Debounce{Wait: 5 * time.Second},
+			steps: []step{
+				{wait: true, want: suppressed},
+				{after: time.Second, wait: true, want: suppressed},
+			},
+			end:      5 * time.Second,
+			wantLast: TriggerResult{TriggerName: "Debounced", SuppressedCalls: 2},
+		},
+		{
+			name:     "Trailing with max wait",
+			debounce: Debounce{Wait: 5 * time.Second, MaxWait: 8 * time.Second},
+			steps: []step{
+				{wait: true, want: suppressed},
+				{after: 4 * time.Second, wait: true, want: suppressed},
+			},
+			// Серия началась 8 секунд назад, дальше не ждём
+			end:      4 * time.Second,
+			wantLast: TriggerResult{TriggerName: "Debounced", SuppressedCalls: 2},
+		},
+		{
+			name:     "Leading",
+			debounce: Debounce{Wait: 5 * time.Second, Edge: DebounceLeading},
+			steps: []step{
+				{want: ran},
+				{after: time.Second, want: suppressed},
+				{after: 4 * time.Second, want: suppressed},
+				{after: 5 * time.Second, want: TriggerResult{TriggerName: "Debounced", SuppressedCalls: 2}},
+			},
+			wantLast: suppressed,
+		},
+		{
+			name:     "Leading with max wait",
+			debounce: Debounce{Wait: 5 * time.Second, Edge: DebounceLeading, MaxWait: 6 * time.Second},
+			steps: []step{
+				{want: ran},
+				{after: 4 * time.Second, want: suppressed},
+				// Пауза меньше Wait, серия продолжается, но с первого выполнения прошло MaxWait
+				{after: 2 * time.Second, want: TriggerResult{TriggerName: "Debounced", SuppressedCalls: 1}},
+			},
+			wantLast: suppressed,
+		},
+		{
+			name:     "Both",
+			debounce: Debounce{Wait: 5 * time.Second, Edge: DebounceBoth},
+			steps: []step{
+				{want: ran},
+				{after: time.Second, wait: true, want: suppressed},
+			},
+			end:      5 * time.Second,
+			wantLast: TriggerResult{TriggerName: "Debounced", SuppressedCalls: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					fake  = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+					e     = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+					ctx   = context.Background()
+					ev, _ = event.NewEvent(
+						event.Args{
+							TriggerName: "Debounced",
+							Fun: func(ctx context.Context) string {
+								return "OK"
+							},
+						},
+					)
+					timers = e.(*eventLoop).scheduler.(interface{ Len() int })
+				)
+				if err := e.RegisterEvent(ctx, ev); err != nil {
+					t.Fatal(err)
+				}
+				if err := e.ConfigureTrigger("Debounced", TriggerConfig{Debounce: tt.debounce}); err != nil {
+					t.Fatal(err)
+				}
+
+				// trigger вызывает триггер в своей горутине и, если вызов должен ждать, ждёт, пока он заведёт таймер
+				trigger := func(wait bool) <-chan TriggerResult {
+					results := make(chan TriggerResult, 1)
+					go func() {
+						result, err := e.Trigger(ctx, "Debounced")
+						if err != nil {
+							t.Error(err)
+						}
+						results <- result
+					}()
+					if wait {
+						waitTimer(t, timers)
+					}
+					return results
+				}
+				check := func(name string, got, want TriggerResult) {
+					if got.Suppressed != want.Suppressed || got.SuppressedBy != want.SuppressedBy ||
+						got.SuppressedCalls != want.SuppressedCalls {
+						t.Errorf("%v: Trigger() = %+v, want %+v", name, got, want)
+					}
+					if !want.Suppressed && len(got.Events) != 1 {
+						t.Errorf("%v: Trigger() ran %v events, want 1", name, len(got.Events))
+					}
+				}
+
+				var (
+					waiting     <-chan TriggerResult
+					waitingWant TriggerResult
+				)
+				for i, s := range tt.steps {
+					fake.Advance(s.after)
+					results := trigger(s.wait)
+					if waiting != nil {
+						check("waiting call", <-waiting, waitingWant)
+						waiting = nil
+					}
+					if s.wait {
+						waiting, waitingWant = results, s.want
+						continue
+					}
+					check(fmt.Sprintf("step %v", i), <-results, s.want)
+				}
+
+				last := trigger(false)
+				if waiting != nil {
+					check("waiting call", <-waiting, waitingWant)
+				}
+				if tt.end > 0 {
+					waitTimer(t, timers)
+				}
+				fake.Advance(tt.end)
+				check("last call", <-last, tt.wantLast)
+			},
+		)
+	}
+}
+
+// waitTimer ждёт, пока вызов триггера, придержанный debounce, заведёт таймер конца серии
+func waitTimer(t *testing.T, timers interface{ Len() int }) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); timers.Len() == 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("trigger call is not waiting")
+		}
+	}
+}
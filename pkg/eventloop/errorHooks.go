@@ -0,0 +1,48 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+// ErrorHook вызывается для каждого выполнения события, которое завершилось ошибкой или паникой
+type ErrorHook func(ctx context.Context, result event.Result)
+
+// OnError добавляет хуки, которые вызываются при ошибке в любом событии: по триггеру, интервальном, слушателе и
+// системных BEFORE_TRIGGER/AFTER_TRIGGER. После них выполняются системные события ON_ERROR.
+func (e *eventLoop) OnError(hooks ...ErrorHook) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	e.errorHooks = append(e.errorHooks, hooks...)
+}
+
+// handleResult логирует ошибку выполнения события и передаёт результат хукам ошибок
+func (e *eventLoop) handleResult(ctx context.Context, result event.Result) event.Result {
+	if result.Err == nil {
+		return result
+	}
+
+	var panicErr *event.PanicError
+	if errors.As(result.Err, &panicErr) {
+		e.logger.Errorw(
+			"Event function panic",
+			"eventId", result.UUID,
+			"panic", panicErr.Value,
+			"stack", string(panicErr.Stack),
+		)
+	} else {
+		e.logger.Errorw("Event function error", "eventId", result.UUID, "error", result.Err)
+	}
+
+	e.mx.RLock()
+	hooks := e.errorHooks
+	e.mx.RUnlock()
+
+	for _, hook := range hooks {
+		hook(ctx, result)
+	}
+	_ = e.lifecycle(ctx, Lifecycle{Stage: ON_ERROR, Events: []string{result.UUID}, Result: &result})
+	return result
+}
@@ -0,0 +1,96 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+)
+
+func Test_eventLoop_OnError(t *testing.T) {
+	var (
+		lgger      = newTestLogger()
+		errTrigger = errors.New("trigger error")
+		evErr, _   = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig", ErrFun: func(ctx context.Context) (string, error) {
+					return "", errTrigger
+				},
+			},
+		)
+		evPanic, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig", Fun: func(ctx context.Context) string {
+					panic("PANIC")
+				},
+			},
+		)
+		evOK, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig", Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	tests := []struct {
+		name       string
+		events     []event.Interface
+		wantHooked int
+	}{
+		{
+			name:       "Error and panic",
+			events:     []event.Interface{evErr, evPanic, evOK},
+			wantHooked: 2,
+		},
+		{
+			name:       "No errors",
+			events:     []event.Interface{evOK},
+			wantHooked: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					mx     sync.Mutex
+					hooked []event.Result
+				)
+				e := &eventLoop{
+					clock:  clock.New(),
+					events: eventsContainer.New(),
+					mx:     &sync.RWMutex{},
+					logger: lgger,
+				}
+				e.OnError(
+					func(ctx context.Context, result event.Result) {
+						mx.Lock()
+						defer mx.Unlock()
+						hooked = append(hooked, result)
+					},
+				)
+				e.RegisterEvent(context.Background(), tt.events...)
+
+				got, err := e.Trigger(context.Background(), "Trig")
+				if err != nil {
+					t.Errorf("Trigger() error = %v", err)
+				}
+				if len(got.Events) != len(tt.events) {
+					t.Errorf("Trigger() events = %v, want %v", len(got.Events), len(tt.events))
+				}
+				if len(hooked) != tt.wantHooked {
+					t.Errorf("OnError() hooked = %v, want %v", len(hooked), tt.wantHooked)
+				}
+				for _, res := range hooked {
+					if res.Err == nil || res.Status != event.StatusFailed {
+						t.Errorf("OnError() result = %+v", res)
+					}
+				}
+			},
+		)
+	}
+}
@@ -1,7 +1,10 @@
 package after
 
 import (
+	"sync"
 	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 )
 
 type Args struct {
@@ -10,16 +13,25 @@ type Args struct {
 }
 
 type component struct {
-	date    Args
-	breakCh chan bool
-	isDone  bool
+	date  Args
+	clock clock.Interface
+
+	mx sync.Mutex
+	// waits - ожидания, которые ещё не закончились
+	waits map[*wait]struct{}
+}
+
+// wait - одно ожидание даты: таймер часов и функция, которой сообщить, чем оно закончилось
+type wait struct {
+	timer clock.Timer
+	done  func(waited bool)
 }
 
 func New(after Args) Interface {
 	after.Date = after.Date.AddDate(-1, -1, -1)
 	return &component{
-		date:    after,
-		breakCh: make(chan bool),
+		date:  after,
+		clock: clock.New(),
 	}
 }
 
@@ -27,25 +39,70 @@ func (e *component) GetDuration() time.Duration {
 	if e.date.IsRelative {
 		return e.date.Date.Sub(time.Time{})
 	}
-	return time.Until(e.date.Date)
+	return e.date.Date.Sub(e.clock.Now())
 }
 
-func (e *component) GetBreakChannel() chan bool {
-	return e.breakCh
+// SetClock подменяет часы, по которым считается время ожидания. Менеджер событий передаёт свои часы при регистрации
+func (e *component) SetClock(c clock.Interface) {
+	e.clock = c
 }
 
 func (e *component) IsDone() bool {
-	return e.isDone
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	return len(e.waits) == 0
 }
 
-func (e *component) Wait() {
-	e.isDone = false
-	timer := time.NewTimer(e.GetDuration())
-	select {
-	case <-e.breakCh:
-		timer.Stop()
-	case <-timer.C:
+func (e *component) Schedule(done func(waited bool)) {
+	w := &wait{done: done}
+	e.mx.Lock()
+	if e.waits == nil {
+		e.waits = make(map[*wait]struct{})
+	}
+	e.waits[w] = struct{}{}
+	e.mx.Unlock()
+
+	// Таймер заводится без блокировки: часы могут вызвать функцию сразу
+	timer := e.clock.AfterFunc(
+		e.GetDuration(), func() {
+			if e.finish(w) {
+				done(true)
+			}
+		},
+	)
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	w.timer = timer
+	if _, waiting := e.waits[w]; !waiting {
+		// Ожидание прервали, пока заводился таймер
 		timer.Stop()
 	}
-	e.isDone = true
+}
+
+func (e *component) Break() bool {
+	e.mx.Lock()
+	waits := e.waits
+	e.waits = nil
+	for w := range waits {
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+	}
+	e.mx.Unlock()
+
+	for w := range waits {
+		w.done(false)
+	}
+	return len(waits) > 0
+}
+
+// finish снимает ожидание w. false - оно уже закончилось
+func (e *component) finish(w *wait) bool {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if _, waiting := e.waits[w]; !waiting {
+		return false
+	}
+	delete(e.waits, w)
+	return true
 }
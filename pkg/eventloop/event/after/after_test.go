@@ -4,8 +4,12 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 )
 
+var now = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
 func Test_eventAfter_GetDurationSec(t *testing.T) {
 	type fields struct {
 		date Args
@@ -21,7 +25,7 @@ func Test_eventAfter_GetDurationSec(t *testing.T) {
 		{
 			name: "Absolute",
 			fields: fields{
-				Args{Date: time.Now().Add(time.Second * 3)},
+				Args{Date: now.Add(time.Second * 3)},
 			},
 			want: want1,
 		},
@@ -40,7 +44,8 @@ func Test_eventAfter_GetDurationSec(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := component{
-					date: tt.fields.date,
+					date:  tt.fields.date,
+					clock: clock.NewFake(now),
 				}
 				if got := e.GetDuration(); got != tt.want {
 					t.Errorf("GetDuration() = %v, want %v", got, tt.want)
@@ -69,11 +74,9 @@ func TestNew(t *testing.T) {
 			tt.name, func(t *testing.T) {
 				got := New(tt.args)
 				tt.want = &component{
-					date:    Args{Date: currentDate, IsRelative: true},
-					breakCh: got.GetBreakChannel(),
+					date: Args{Date: currentDate, IsRelative: true},
 				}
-				if got.IsDone() != tt.want.IsDone() || got.GetDuration() != tt.want.GetDuration() || got.
-					GetBreakChannel() != tt.want.GetBreakChannel() {
+				if got.IsDone() != tt.want.IsDone() || got.GetDuration() != tt.want.GetDuration() {
 					t.Errorf("New() = %v, want %v", got, tt.want)
 				}
 			},
@@ -81,64 +84,32 @@ func TestNew(t *testing.T) {
 	}
 }
 
-func Test_eventAfter_GetBreakChannel(t *testing.T) {
-	var ch = make(chan bool)
-	type fields struct {
-		date    Args
-		breakCh chan bool
-		isDone  bool
-	}
+func Test_eventAfter_IsDone(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
-		want   chan bool
+		name string
+		// schedule - сколько ожиданий запустить
+		schedule int
+		want     bool
 	}{
 		{
-			name:   "Default",
-			fields: fields{date: Args{Date: time.Now()}, breakCh: ch},
-			want:   ch,
+			name: "Default",
+			want: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(
-			tt.name, func(t *testing.T) {
-				e := &component{
-					date:    tt.fields.date,
-					breakCh: tt.fields.breakCh,
-					isDone:  tt.fields.isDone,
-				}
-				if got := e.GetBreakChannel(); !reflect.DeepEqual(got, tt.want) {
-					t.Errorf("GetBreakChannel() = %v, want %v", got, tt.want)
-				}
-			},
-		)
-	}
-}
-
-func Test_eventAfter_IsDone(t *testing.T) {
-	type fields struct {
-		date    Args
-		breakCh chan bool
-		isDone  bool
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		want   bool
-	}{
 		{
-			name:   "Default",
-			fields: fields{date: Args{Date: time.Now()}, breakCh: make(chan bool), isDone: true},
-			want:   true,
+			name:     "Waiting",
+			schedule: 2,
+			want:     false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &component{
-					date:    tt.fields.date,
-					breakCh: tt.fields.breakCh,
-					isDone:  tt.fields.isDone,
+					date:  Args{Date: now.Add(time.Minute)},
+					clock: clock.NewFake(now),
+				}
+				for i := 0; i < tt.schedule; i++ {
+					e.Schedule(func(waited bool) {})
 				}
 				if got := e.IsDone(); got != tt.want {
 					t.Errorf("IsDone() = %v, want %v", got, tt.want)
@@ -148,58 +119,83 @@ func Test_eventAfter_IsDone(t *testing.T) {
 	}
 }
 
-func Test_eventAfter_Wait(t *testing.T) {
-	type fields struct {
-		date    Args
-		breakCh chan bool
-		isDone  bool
-	}
+func Test_eventAfter_Schedule(t *testing.T) {
 	tests := []struct {
-		name      string
-		fields    fields
-		breakFunc func(ch chan bool)
+		name    string
+		date    Args
+		isBreak bool
 	}{
 		{
 			name: "Absolute",
-			fields: fields{
-				date:    Args{Date: time.Now().Add(time.Millisecond * 20)},
-				breakCh: make(chan bool),
-			},
+			date: Args{Date: now.Add(time.Millisecond * 20)},
 		},
 		{
 			name: "Relative",
-			fields: fields{
-				date:    Args{Date: time.Time{}.Add(time.Millisecond * 20), IsRelative: true},
-				breakCh: make(chan bool),
-			},
+			date: Args{Date: time.Time{}.Add(time.Millisecond * 20), IsRelative: true},
 		},
 		{
-			name: "Break by channel",
-			fields: fields{
-				date:    Args{Date: time.Now().Add(time.Second * 3)},
-				breakCh: make(chan bool),
-			},
-			breakFunc: func(ch chan bool) {
-				ch <- true
-			},
+			name:    "Break",
+			date:    Args{Date: now.Add(time.Second * 3)},
+			isBreak: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				e := &component{
-					date:    tt.fields.date,
-					breakCh: tt.fields.breakCh,
-					isDone:  tt.fields.isDone,
+				var (
+					fake = clock.NewFake(now)
+					e    = &component{date: tt.date, clock: fake}
+					// results - чем закончились ожидания, по порядку. Fake вызывает функции в горутине теста
+					results []bool
+				)
+				for i := 0; i < 2; i++ {
+					e.Schedule(
+						func(waited bool) {
+							results = append(results, waited)
+						},
+					)
+				}
+
+				if tt.isBreak {
+					if !e.Break() {
+						t.Error("Break() = false, want true")
+					}
+				} else {
+					fake.Advance(19 * time.Millisecond)
+					if len(results) != 0 {
+						t.Fatalf("Schedule() finished before time")
+					}
+					fake.Advance(time.Millisecond)
 				}
-				if tt.breakFunc != nil {
-					go tt.breakFunc(e.GetBreakChannel())
+				if want := []bool{!tt.isBreak, !tt.isBreak}; !reflect.DeepEqual(results, want) {
+					t.Errorf("Schedule() results = %v, want %v", results, want)
 				}
-				e.Wait()
 				if got := e.IsDone(); !got {
 					t.Errorf("IsDone() = %v, want %v", got, true)
 				}
+				if e.Break() {
+					t.Error("Break() after all waits finished = true, want false")
+				}
+				fake.Advance(time.Hour)
+				if len(results) != 2 {
+					t.Errorf("Schedule() results = %v, want each wait finished once", results)
+				}
 			},
 		)
 	}
 }
+
+func Test_eventAfter_SetClock(t *testing.T) {
+	var (
+		e    = New(Args{Date: now.Add(time.Hour).AddDate(1, 1, 1)})
+		fake = clock.NewFake(now)
+	)
+	e.SetClock(fake)
+	if got := e.GetDuration(); got != time.Hour {
+		t.Errorf("GetDuration() = %v, want %v", got, time.Hour)
+	}
+	fake.Advance(time.Minute)
+	if got := e.GetDuration(); got != 59*time.Minute {
+		t.Errorf("GetDuration() after Advance() = %v, want %v", got, 59*time.Minute)
+	}
+}
@@ -1,10 +1,19 @@
 package after
 
-import "time"
+import (
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+)
 
 type Interface interface {
 	GetDuration() time.Duration
-	GetBreakChannel() chan bool
+	// IsDone - ни одно ожидание даты события сейчас не идёт
 	IsDone() bool
-	Wait()
+	// Schedule ждёт наступления даты события на часах события, не занимая горутину: done вызывается с true в горутине
+	// часов, когда дата наступила, или с false в горутине Break, если ожидание прервали
+	Schedule(done func(waited bool))
+	// Break прерывает все идущие ожидания. true - было что прерывать
+	Break() bool
+	SetClock(c clock.Interface)
 }
@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DSTPolicy - как выполнять запуски, попавшие на перевод часов. Весной часть времени пропускается (в Europe/Berlin
+// после 01:59:59 сразу наступает 03:00:00), осенью час повторяется дважды (02:00-02:59 сначала по летнему, затем по
+// зимнему времени)
+type DSTPolicy string
+
+const (
+	// DSTShift - запуск в пропущенное время выполняется в момент перевода часов (02:30 -> 03:00), повторённое время
+	// выполняется один раз, в первый проход (по умолчанию)
+	DSTShift DSTPolicy = "SHIFT"
+	// DSTSkip - запуск в пропущенное время не выполняется, повторённое время выполняется один раз, в первый проход
+	DSTSkip DSTPolicy = "SKIP"
+	// DSTRepeat - запуск в пропущенное время выполняется в момент перевода часов, повторённое время выполняется дважды
+	DSTRepeat DSTPolicy = "REPEAT"
+)
+
+// dstWindow - на сколько максимум переводят часы. Рядом с переводом следующий запуск ищется с таким запасом
+const dstWindow = 3 * time.Hour
+
+// date - день календаря без времени, для дат-исключений
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func newDate(t time.Time) date {
+	return date{year: t.Year(), month: t.Month(), day: t.Day()}
+}
+
+func validateDSTPolicy(policy DSTPolicy) error {
+	switch policy {
+	case DSTShift, DSTSkip, DSTRepeat:
+		return nil
+	default:
+		return fmt.Errorf("unknown DST policy %q", policy)
+	}
+}
+
+// wallClock переносит показания часов t в UTC. В UTC нет перевода часов, поэтому по таким значениям удобно шагать
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// nearTransition - в пределах dstWindow от t в loc переводят часы
+func nearTransition(t time.Time, loc *time.Location) bool {
+	_, before := t.Add(-dstWindow).In(loc).Zone()
+	_, current := t.In(loc).Zone()
+	_, after := t.Add(dstWindow).In(loc).Zone()
+	return before != current || current != after
+}
+
+// resolve возвращает моменты времени по возрастанию, в которые часы в loc показывают wall (см. wallClock). Обычно такой
+// момент один, при переводе часов - ноль или два, что с ними делать, решает политика
+func (e *component) resolve(wall time.Time, loc *time.Location) []time.Time {
+	// Смещение зоны за сутки до и через сутки после: если между ними перевод часов, они разные
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var instants []time.Time
+	for _, offset := range []int{before, after} {
+		at := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if wallClock(at).Equal(wall) && (len(instants) == 0 || !instants[0].Equal(at)) {
+			instants = append(instants, at)
+		}
+	}
+
+	switch {
+	case len(instants) == 0:
+		if e.dst == DSTSkip {
+			return nil
+		}
+		return []time.Time{transition(wall.Add(-time.Duration(after)*time.Second), after, loc)}
+	case len(instants) > 1 && e.dst != DSTRepeat:
+		return instants[:1]
+	default:
+		return instants
+	}
+}
+
+// transition находит момент перевода часов на смещение offset в течение dstWindow после from
+func transition(from time.Time, offset int, loc *time.Location) time.Time {
+	seconds := sort.Search(
+		int(dstWindow/time.Second), func(i int) bool {
+			_, current := from.Add(time.Duration(i) * time.Second).In(loc).Zone()
+			return current == offset
+		},
+	)
+	return from.Add(time.Duration(seconds) * time.Second).In(loc)
+}
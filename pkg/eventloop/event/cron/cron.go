@@ -0,0 +1,149 @@
+package cron
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Args - расписание cron события
+type Args struct {
+	// Expression - стандартное выражение из 5 полей, из 6 полей с секундами первым полем, либо макрос
+	// (@yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly)
+	Expression string
+	// Location - часовой пояс, в котором задано расписание ("в 09:00 по Берлину"). По умолчанию - пояс часов менеджера
+	Location *time.Location
+	// DST - что делать с запусками, попавшими на перевод часов в Location. По умолчанию DSTShift
+	DST DSTPolicy
+	// Exclude - даты, в которые событие не запускается (праздники). Учитываются только год, месяц и день в Location
+	Exclude []time.Time
+}
+
+// component - событие, запускаемое по cron расписанию. Как и интервальное, имеет канал для остановки
+type component struct {
+	expression string
+	schedule   *schedule
+	location   *time.Location
+	dst        DSTPolicy
+	exclude    map[date]struct{}
+	// isRunning меняет горутина события, а читают регистрация, удаление и Shutdown
+	isRunning atomic.Bool
+	quit      chan bool
+}
+
+// searchYears - насколько далеко вперёд ищем следующий запуск. Выражение вроде "0 0 30 2 *" не сработает никогда
+const searchYears = 5
+
+func New(args Args) (Interface, error) {
+	s, err := parse(args.Expression)
+	if err != nil {
+		return nil, err
+	}
+	if args.DST == "" {
+		args.DST = DSTShift
+	}
+	if err = validateDSTPolicy(args.DST); err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[date]struct{}, len(args.Exclude))
+	for _, day := range args.Exclude {
+		exclude[newDate(day)] = struct{}{}
+	}
+	return &component{
+		expression: args.Expression,
+		schedule:   s,
+		location:   args.Location,
+		dst:        args.DST,
+		exclude:    exclude,
+		// Сигнал остановки не теряется, даже если горутина события ещё не дошла до ожидания
+		quit: make(chan bool, 1),
+	}, nil
+}
+
+func (e *component) Expression() string {
+	return e.expression
+}
+
+func (e *component) GetQuitChannel() chan bool {
+	return e.quit
+}
+
+func (e *component) IsRunning() bool {
+	return e.isRunning.Load()
+}
+
+func (e *component) SetRunning(run bool) {
+	e.isRunning.Store(run)
+}
+
+func (e *component) Location() *time.Location {
+	return e.location
+}
+
+func (e *component) Next(after time.Time) time.Time {
+	loc := e.location
+	if loc == nil {
+		loc = after.Location()
+	}
+	// Шагаем по показаниям часов в loc. Рядом с переводом часов одни и те же показания бывают дважды, поэтому начинаем
+	// раньше и выбираем самый ранний момент после after
+	from, window := wallClock(after.In(loc)), time.Duration(0)
+	if nearTransition(after, loc) {
+		from, window = from.Add(-dstWindow), dstWindow
+	}
+	limit := from.AddDate(searchYears, 0, 0)
+
+	var next time.Time
+	for wall := e.nextWall(from, limit); !wall.IsZero(); wall = e.nextWall(wall.Add(time.Second), limit) {
+		if !next.IsZero() && wall.After(wallClock(next).Add(window)) {
+			break
+		}
+		for _, at := range e.resolve(wall, loc) {
+			if at.After(after) && (next.IsZero() || at.Before(next)) {
+				next = at
+			}
+		}
+	}
+	return next
+}
+
+// nextWall возвращает ближайшие показания часов не раньше from, подходящие под расписание и не попадающие на даты
+// исключений. Нулевое время - до limit таких нет
+func (e *component) nextWall(from, limit time.Time) time.Time {
+	s := e.schedule
+	t := from
+	for !t.After(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if _, excluded := e.exclude[newDate(t)]; excluded || !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.second, t.Second()) {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches - по стандарту cron, если ограничены и день месяца, и день недели, достаточно совпадения любого из них
+func (s *schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
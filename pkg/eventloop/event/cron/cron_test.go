@@ -0,0 +1,229 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    Args
+		wantErr bool
+	}{
+		{
+			name: "Default",
+			args: Args{Expression: "*/5 * * * *"},
+		},
+		{
+			name:    "Bad expression",
+			args:    Args{Expression: "every day"},
+			wantErr: true,
+		},
+		{
+			name:    "Bad DST policy",
+			args:    Args{Expression: "@daily", DST: "NEVER"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Expression() != tt.args.Expression || got.IsRunning() || got.GetQuitChannel() == nil) {
+				t.Errorf("New() = %v", got)
+			}
+		})
+	}
+}
+
+func Test_component_Next(t *testing.T) {
+	var (
+		date = func(value string) time.Time {
+			d, err := time.Parse("2006-01-02 15:04:05", value)
+			if err != nil {
+				panic(err)
+			}
+			return d
+		}
+	)
+	tests := []struct {
+		name       string
+		expression string
+		after      string
+		want       string
+	}{
+		{
+			name:       "Every minute",
+			expression: "* * * * *",
+			after:      "2023-03-10 12:30:15",
+			want:       "2023-03-10 12:31:00",
+		},
+		{
+			name:       "Every 20 seconds",
+			expression: "*/20 * * * * *",
+			after:      "2023-03-10 12:30:40",
+			want:       "2023-03-10 12:31:00",
+		},
+		{
+			name:       "Strictly after",
+			expression: "30 12 * * *",
+			after:      "2023-03-10 12:30:00",
+			want:       "2023-03-11 12:30:00",
+		},
+		{
+			name:       "Hourly",
+			expression: "@hourly",
+			after:      "2023-12-31 23:10:00",
+			want:       "2024-01-01 00:00:00",
+		},
+		{
+			name:       "Weekdays",
+			expression: "0 9 * * MON-FRI",
+			after:      "2023-03-10 10:00:00",
+			want:       "2023-03-13 09:00:00",
+		},
+		{
+			name:       "Day of month or day of week",
+			expression: "0 0 15 * SUN",
+			after:      "2023-03-10 00:00:00",
+			want:       "2023-03-12 00:00:00",
+		},
+		{
+			name:       "Leap day",
+			expression: "0 0 29 2 *",
+			after:      "2023-01-01 00:00:00",
+			want:       "2024-02-29 00:00:00",
+		},
+		{
+			name:       "Never",
+			expression: "0 0 30 2 *",
+			after:      "2023-01-01 00:00:00",
+			want:       "0001-01-01 00:00:00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(Args{Expression: tt.expression})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Next(date(tt.after)); !got.Equal(date(tt.want)) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_component_Next_Calendar(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		date = func(value string) time.Time {
+			d, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				panic(err)
+			}
+			return d
+		}
+	)
+	tests := []struct {
+		name       string
+		expression string
+		dst        DSTPolicy
+		exclude    []time.Time
+		after      string
+		// want - последовательные запуски после after
+		want []string
+	}{
+		{
+			name:       "Location",
+			expression: "0 9 * * MON-FRI",
+			after:      "2026-03-06T09:30:00Z",
+			want:       []string{"2026-03-09T09:00:00+01:00", "2026-03-10T09:00:00+01:00"},
+		},
+		{
+			name:       "Same wall clock across spring transition",
+			expression: "0 9 * * *",
+			after:      "2026-03-28T10:00:00+01:00",
+			want:       []string{"2026-03-29T09:00:00+02:00"},
+		},
+		{
+			name:       "Skipped time, shift",
+			expression: "30 2 * * *",
+			after:      "2026-03-28T03:00:00+01:00",
+			want:       []string{"2026-03-29T03:00:00+02:00", "2026-03-30T02:30:00+02:00"},
+		},
+		{
+			name:       "Skipped time, skip",
+			expression: "30 2 * * *",
+			dst:        DSTSkip,
+			after:      "2026-03-28T03:00:00+01:00",
+			want:       []string{"2026-03-30T02:30:00+02:00"},
+		},
+		{
+			name:       "Repeated time, once",
+			expression: "30 2 * * *",
+			after:      "2026-10-24T03:00:00+02:00",
+			want:       []string{"2026-10-25T02:30:00+02:00", "2026-10-26T02:30:00+01:00"},
+		},
+		{
+			name:       "Repeated time, repeat",
+			expression: "30 2 * * *",
+			dst:        DSTRepeat,
+			after:      "2026-10-24T03:00:00+02:00",
+			want: []string{
+				"2026-10-25T02:30:00+02:00", "2026-10-25T02:30:00+01:00", "2026-10-26T02:30:00+01:00",
+			},
+		},
+		{
+			name:       "Repeated hour, every 15 minutes",
+			expression: "*/15 2 * * *",
+			dst:        DSTRepeat,
+			after:      "2026-10-25T02:40:00+02:00",
+			want: []string{
+				"2026-10-25T02:45:00+02:00", "2026-10-25T02:00:00+01:00", "2026-10-25T02:15:00+01:00",
+			},
+		},
+		{
+			name:       "Exclusion dates",
+			expression: "0 9 * * MON-FRI",
+			exclude: []time.Time{
+				time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+			},
+			after: "2026-12-23T10:00:00+01:00",
+			want:  []string{"2026-12-28T09:00:00+01:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(Args{Expression: tt.expression, Location: berlin, DST: tt.dst, Exclude: tt.exclude})
+			if err != nil {
+				t.Fatal(err)
+			}
+			after := date(tt.after)
+			for _, want := range tt.want {
+				got := e.Next(after)
+				if !got.Equal(date(want)) {
+					t.Fatalf("Next(%v) = %v, want %v", after, got, want)
+				}
+				if got.Location() != berlin {
+					t.Errorf("Next() location = %v, want %v", got.Location(), berlin)
+				}
+				after = got
+			}
+		})
+	}
+}
+
+func Test_component_SetRunning(t *testing.T) {
+	e, _ := New(Args{Expression: "@daily"})
+	e.SetRunning(true)
+	if !e.IsRunning() {
+		t.Errorf("IsRunning() = false, want true")
+	}
+}
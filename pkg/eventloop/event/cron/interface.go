@@ -0,0 +1,14 @@
+package cron
+
+import "time"
+
+type Interface interface {
+	Expression() string
+	// Location - часовой пояс расписания, nil - пояс времени, переданного в Next
+	Location() *time.Location
+	// Next возвращает ближайшее время запуска строго после after, в Location. Нулевое время - запусков больше не будет
+	Next(after time.Time) time.Time
+	GetQuitChannel() chan bool
+	IsRunning() bool
+	SetRunning(run bool)
+}
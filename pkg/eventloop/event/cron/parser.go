@@ -0,0 +1,162 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bounds - допустимые значения поля расписания
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondBounds = bounds{min: 0, max: 59}
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{
+		min: 1, max: 12,
+		names: map[string]int{
+			"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+			"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+		},
+	}
+	// 7 - тоже воскресенье
+	dowBounds = bounds{
+		min: 0, max: 7,
+		names: map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6},
+	}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// schedule - разобранное выражение. Каждое поле - битовая маска допустимых значений
+type schedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar, dowStar - поле задано через *. Если ограничены оба дня (месяца и недели), достаточно совпадения любого
+	domStar, dowStar bool
+}
+
+// parse разбирает выражение из 5 полей (минуты, часы, день месяца, месяц, день недели), из 6 полей (секунды первым
+// полем) или макрос (@yearly, @monthly, @weekly, @daily, @hourly)
+func parse(expression string) (*schedule, error) {
+	expr := strings.TrimSpace(expression)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression %q: expected 5 or 6 fields, got %v", expression, len(fields))
+	}
+
+	var (
+		s   = &schedule{}
+		err error
+	)
+	for _, f := range []struct {
+		field  string
+		bounds bounds
+		dest   *uint64
+	}{
+		{fields[0], secondBounds, &s.second},
+		{fields[1], minuteBounds, &s.minute},
+		{fields[2], hourBounds, &s.hour},
+		{fields[3], domBounds, &s.dom},
+		{fields[4], monthBounds, &s.month},
+		{fields[5], dowBounds, &s.dow},
+	} {
+		if *f.dest, err = parseField(f.field, f.bounds); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expression, err)
+		}
+	}
+
+	// Воскресенье может быть задано и как 0, и как 7
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = isStar(fields[3])
+	s.dowStar = isStar(fields[5])
+	return s, nil
+}
+
+func isStar(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseField разбирает поле: список через запятую из *, значений, диапазонов a-b, с шагом /n
+func parseField(field string, b bounds) (mask uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i != -1 {
+			rangePart = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+		}
+
+		var from, to int
+		switch {
+		case isStar(rangePart):
+			from, to = b.min, b.max
+		case strings.Contains(rangePart, "-"):
+			i := strings.Index(rangePart, "-")
+			if from, err = parseValue(rangePart[:i], b); err != nil {
+				return 0, err
+			}
+			if to, err = parseValue(rangePart[i+1:], b); err != nil {
+				return 0, err
+			}
+		default:
+			if from, err = parseValue(rangePart, b); err != nil {
+				return 0, err
+			}
+			to = from
+			// a/n - от a до конца с шагом n
+			if strings.Contains(part, "/") {
+				to = b.max
+			}
+		}
+		if from > to {
+			return 0, fmt.Errorf("bad range in %q", part)
+		}
+
+		for v := from; v <= to; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToUpper(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("bad value %q", value)
+	}
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("value %v out of range [%v, %v]", v, b.min, b.max)
+	}
+	return v, nil
+}
+
+func has(mask uint64, v int) bool {
+	return mask&(1<<uint(v)) != 0
+}
@@ -0,0 +1,88 @@
+package cron
+
+import (
+	"testing"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       schedule
+		wantErr    bool
+	}{
+		{
+			name:       "Every minute",
+			expression: "* * * * *",
+			want: schedule{
+				second: 1, minute: 1<<60 - 1, hour: 1<<24 - 1, dom: 1<<32 - 2, month: 1<<13 - 2, dow: 1<<7 - 1,
+				domStar: true, dowStar: true,
+			},
+		},
+		{
+			name:       "With seconds, lists, ranges, steps and names",
+			expression: "*/20 0,30 9-11 1 JAN-MAR/2 MON-FRI",
+			want: schedule{
+				second: 1 | 1<<20 | 1<<40, minute: 1 | 1<<30, hour: 1<<9 | 1<<10 | 1<<11, dom: 1 << 1,
+				month: 1<<1 | 1<<3, dow: 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5,
+			},
+		},
+		{
+			name:       "Sunday as 7",
+			expression: "0 0 * * 7",
+			want:       schedule{second: 1, minute: 1, hour: 1, dom: 1<<32 - 2, month: 1<<13 - 2, dow: 1, domStar: true},
+		},
+		{
+			name:       "Start with step",
+			expression: "0 5/20 * * * ?",
+			want: schedule{
+				second: 1, minute: 1<<5 | 1<<25 | 1<<45, hour: 1<<24 - 1, dom: 1<<32 - 2, month: 1<<13 - 2,
+				dow: 1<<7 - 1, domStar: true, dowStar: true,
+			},
+		},
+		{
+			name:       "Macro",
+			expression: "@Daily",
+			want: schedule{
+				second: 1, minute: 1, hour: 1, dom: 1<<32 - 2, month: 1<<13 - 2, dow: 1<<7 - 1,
+				domStar: true, dowStar: true,
+			},
+		},
+		{
+			name:       "Wrong number of fields",
+			expression: "* * * *",
+			wantErr:    true,
+		},
+		{
+			name:       "Out of range",
+			expression: "0 24 * * *",
+			wantErr:    true,
+		},
+		{
+			name:       "Bad step",
+			expression: "*/0 * * * *",
+			wantErr:    true,
+		},
+		{
+			name:       "Bad range",
+			expression: "0 0 10-5 * *",
+			wantErr:    true,
+		},
+		{
+			name:       "Bad name",
+			expression: "0 0 * * FUN",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStop - функция события может вернуть эту ошибку (или обёрнутую в неё), чтобы остановить выполнение оставшихся
+// событий триггера с меньшим приоритетом. Событие при этом считается выполненным (StatusHalted), а не упавшим
+var ErrStop = errors.New("stop trigger chain")
+
+// Stop - сокращение для ErrFunc: return event.Stop("reason")
+func Stop(value string) (string, error) {
+	return value, ErrStop
+}
+
+// PanicError - ошибка, в которую превращается паника внутри функции события. Stack - стек горутины в момент паники
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("event function panic: %v", pe.Value)
+}
+
+// TimeoutError - функция события не уложилась в event.Args.Timeout. errors.Is(err, context.DeadlineExceeded) == true
+type TimeoutError struct {
+	Timeout time.Duration
+	// Done закрывается, когда брошенная по таймауту функция на самом деле вернулась. Функция, которая не следит за
+	// ctx.Done(), работает дольше таймаута, и тот, кто ограничивает число одновременных запусков, должен ждать Done
+	Done <-chan struct{}
+}
+
+func (te *TimeoutError) Error() string {
+	return fmt.Sprintf("event function timed out after %v", te.Timeout)
+}
+
+func (te *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
@@ -5,16 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/cron"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/interval"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/once"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/retry"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
-	"gitlab.com/YSX/eventloop/pkg/eventloop/internal"
 	loggerEventLoop "gitlab.com/YSX/eventloop/pkg/logger"
 )
 
@@ -23,23 +26,49 @@ import (
 type Type string
 
 type Args struct {
+	// TriggerName - имя триггера или шаблон из сегментов через точку: * совпадает ровно с одним сегментом, # - с любым
+	// числом сегментов. Событие с TriggerName "order.*" выполняется при Trigger(ctx, "order.created")
 	TriggerName string
 	Priority    int
 	IsOnce      bool
-	Fun         Func
+	// Fun, ErrFun или PayloadFun - функция события. Если задано несколько, выполняется PayloadFun, затем ErrFun
+	Fun        Func
+	ErrFun     ErrFunc
+	PayloadFun PayloadFunc
 
 	IntervalTime time.Duration
 	DateAfter    after.Args
 	Subscriber   subscriber.Type
+	// Cron - запуск по cron расписанию. Менеджер событий запускает такое событие сразу при регистрации
+	Cron cron.Args
+
+	// Retry - повторы функции события после ошибки, включаются при Retry.MaxAttempts > 1
+	Retry retry.Args
+	// Timeout - ограничение времени одного выполнения функции события. 0 - без ограничения
+	Timeout time.Duration
+	// IntervalOptions - настройки интервального события, учитываются только вместе с IntervalTime
+	IntervalOptions interval.Options
+	// Misfire - что делать с запусками по расписанию, которые опоздали (после сна машины или зависания процесса)
+	Misfire Misfire
+	// Namespace - пространство имён (арендатор) события. Триггеры разных пространств не пересекаются, "" - общее
+	// пространство менеджера событий. См. ValidateNamespace
+	Namespace string
 }
 
 type event struct {
 	uuid        string
+	namespace   string
 	triggerName string
 	priority    int
 	fun         Func
+	errFun      ErrFunc
+	payloadFun  PayloadFunc
 	result      string
+	timeout     time.Duration
+	misfire     Misfire
+	clock       clock.Interface
 
+	// disabled - событие на паузе (SetPaused)
 	disabled bool
 
 	mx sync.Mutex
@@ -48,12 +77,21 @@ type event struct {
 	interval   interval.Interface
 	once       once.Interface
 	after      after.Interface
+	retry      retry.Interface
+	cron       cron.Interface
 }
 
 type Func func(ctx context.Context) string
 
+// ErrFunc - функция события, которая может сообщить об ошибке. Ошибка попадает в Result.Err
+type ErrFunc func(ctx context.Context) (string, error)
+
+// PayloadFunc - функция события, которая получает данные триггера аргументом: payload из TriggerWithPayload, nil -
+// данных нет. Слушатель получает map[string]any, где ключ - UUID события-триггера, а значение - его данные
+type PayloadFunc func(ctx context.Context, payload any) (string, error)
+
 func NewEvent(args Args) (Interface, error) {
-	if args.Fun == nil {
+	if args.Fun == nil && args.ErrFun == nil && args.PayloadFun == nil {
 		return nil, errors.New("no run function")
 	}
 
@@ -62,26 +100,61 @@ func NewEvent(args Args) (Interface, error) {
 		!args.IsOnce &&
 		args.IntervalTime.String() == "0s" &&
 		args.DateAfter == (after.Args{}) &&
-		args.Subscriber == "" {
+		args.Subscriber == "" &&
+		args.Cron.Expression == "" {
 		return nil, errors.New("no event type, event will never trigger")
 	}
 
+	// Ноль - события без интервала, отрицательный период планировщик крутил бы без остановки
+	if args.IntervalTime < 0 {
+		return nil, fmt.Errorf("invalid interval time %v: must be positive", args.IntervalTime)
+	}
+
+	misfire, err := newMisfire(args.Misfire)
+	if err != nil {
+		return nil, err
+	}
+	if args.Namespace != "" {
+		if err = ValidateNamespace(args.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if err = ValidateTriggerName(args.TriggerName); err != nil {
+		return nil, err
+	}
+
 	newEvent := &event{
 		uuid:        uuid.NewString(),
+		namespace:   args.Namespace,
 		fun:         args.Fun,
+		errFun:      args.ErrFun,
+		payloadFun:  args.PayloadFun,
 		triggerName: args.TriggerName,
 		priority:    args.Priority,
+		timeout:     args.Timeout,
+		misfire:     misfire,
+		clock:       clock.New(),
 	}
 
 	if args.IsOnce {
 		newEvent.once = once.NewOnce()
 	}
 	if args.IntervalTime.String() != "0s" {
-		newEvent.interval = interval.NewIntervalEvent(args.IntervalTime)
+		newEvent.interval = interval.NewIntervalEvent(args.IntervalTime, args.IntervalOptions)
 	}
 	if args.DateAfter != (after.Args{}) {
 		newEvent.after = after.New(args.DateAfter)
 	}
+	if args.Cron.Expression != "" {
+		cronComponent, err := cron.New(args.Cron)
+		if err != nil {
+			return nil, err
+		}
+		newEvent.cron = cronComponent
+	}
+	if args.Retry.MaxAttempts > 1 {
+		newEvent.retry = retry.New(args.Retry)
+	}
 
 	switch args.Subscriber {
 	case subscriber.Listener:
@@ -113,9 +186,16 @@ func (ev *event) GetTypes() (out []Type) {
 	if ev.subscriber != nil {
 		out = append(out, "SUBSCRIBER")
 	}
+	if ev.cron != nil {
+		out = append(out, "CRON")
+	}
 	return
 }
 
+func (ev *event) GetNamespace() string {
+	return ev.namespace
+}
+
 func (ev *event) GetTriggerName() string {
 	return ev.triggerName
 }
@@ -128,18 +208,137 @@ func (ev *event) GetPriorityString() string {
 	return strconv.Itoa(ev.priority)
 }
 
-func (ev *event) RunFunction(ctx context.Context) {
+func (ev *event) GetTimeout() time.Duration {
+	return ev.timeout
+}
+
+// SetClock подменяет часы события и его компонентов. Менеджер событий передаёт свои часы при регистрации
+func (ev *event) SetClock(c clock.Interface) {
+	ev.clock = c
+	if ev.after != nil {
+		ev.after.SetClock(c)
+	}
+}
+
+func (ev *event) SetPaused(paused bool) {
+	ev.mx.Lock()
+	defer ev.mx.Unlock()
+	ev.disabled = paused
+}
+
+func (ev *event) IsPaused() bool {
+	ev.mx.Lock()
+	defer ev.mx.Unlock()
+	return ev.disabled
+}
+
+func (ev *event) GetResult() string {
+	ev.mx.Lock()
+	defer ev.mx.Unlock()
+	return ev.result
+}
+
+func (ev *event) GetMisfire() Misfire {
+	return ev.misfire
+}
+
+// RunFunction выполняет функцию события и возвращает результат выполнения. Паника внутри функции не выходит наружу,
+// а превращается в *PanicError в Result.Err. Если у события задан Timeout, каждый вызов получает свой контекст с
+// дедлайном; по его истечении RunFunction не ждёт функцию и возвращает StatusTimeout с *TimeoutError в Result.Err.
+// Когда функция всё же вернётся, закроется TimeoutError.Done
+func (ev *event) RunFunction(ctx context.Context) (result Result) {
 	logger := loggerEventLoop.FromContext(ctx)
 
 	logger.Debugw("Run event function", "eventId", ev.uuid)
-	ev.result = ev.fun(ctx)
-	defer internal.WriteToExecCh(ctx, ev.result)
+	result = Result{UUID: ev.uuid, Priority: ev.priority, Status: StatusDone, Start: ev.clock.Now()}
+
+	result.Value, result.Err = ev.callFunctionWithTimeout(ctx)
+	result.End = ev.clock.Now()
+
+	var timeoutErr *TimeoutError
+	if errors.As(result.Err, &timeoutErr) {
+		result.Status = StatusTimeout
+		logger.Warnw("Event function timed out", "eventId", ev.uuid, "timeout", ev.timeout)
+		return result
+	}
+
+	ev.mx.Lock()
+	ev.result = result.Value
+	ev.mx.Unlock()
+
+	if errors.Is(result.Err, ErrStop) {
+		result.Status, result.Err = StatusHalted, nil
+		logger.Debugw("Event function stopped trigger chain", "eventId", ev.uuid)
+	}
+
+	if result.Err != nil {
+		result.Status = StatusFailed
+		logger.Warnw("Event function failed", "eventId", ev.uuid, "error", result.Err)
+		return result
+	}
 
 	// Активация горутины этого триггера
 	if subber, err := ev.Subscriber(); err == nil && subber.GetType() == subscriber.Trigger {
 		logger.Debugw("Activating trigger goroutine", "eventId", ev.uuid)
-		subber.ChanTrigger() <- struct{}{}
+		if !subber.Notify(Payload(ctx)) {
+			logger.Debugw("Trigger goroutine is not running, listeners are not notified", "eventId", ev.uuid)
+		}
 	}
+	return result
+}
+
+type callResult struct {
+	value string
+	err   error
+}
+
+// callFunctionWithTimeout вызывает функцию в отдельной горутине и ждёт её не дольше ev.timeout. Функция, которая не
+// следит за ctx.Done(), продолжит работать в фоне, но её результат уже никуда не попадёт. Её завершение видно по
+// TimeoutError.Done
+func (ev *event) callFunctionWithTimeout(ctx context.Context) (string, error) {
+	if ev.timeout <= 0 {
+		return ev.callFunction(ctx)
+	}
+
+	fnCtx, cancel := context.WithTimeout(ctx, ev.timeout)
+	defer cancel()
+
+	var (
+		done     = make(chan callResult, 1)
+		finished = make(chan struct{})
+	)
+	go func() {
+		defer close(finished)
+		value, err := ev.callFunction(fnCtx)
+		done <- callResult{value: value, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-fnCtx.Done():
+		// Родительский контекст закончился раньше - это не таймаут события
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", &TimeoutError{Timeout: ev.timeout, Done: finished}
+	}
+}
+
+func (ev *event) callFunction(ctx context.Context) (value string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	if ev.payloadFun != nil {
+		return ev.payloadFun(ctx, Payload(ctx))
+	}
+	if ev.errFun != nil {
+		return ev.errFun(ctx)
+	}
+	return ev.fun(ctx), nil
 }
 
 var eventErrors = struct {
@@ -147,11 +346,15 @@ var eventErrors = struct {
 	interval   error
 	once       error
 	after      error
+	retry      error
+	cron       error
 }{
 	subscriber: errors.New("subscriber"),
 	interval:   errors.New("interval"),
 	once:       errors.New("once"),
 	after:      errors.New("after"),
+	retry:      errors.New("retry"),
+	cron:       errors.New("cron"),
 }
 
 // Subscriber
@@ -171,6 +374,14 @@ func (ev *event) After() (after.Interface, error) {
 	return getSubInterface(ev.after, eventErrors.after)
 }
 
+func (ev *event) Retry() (retry.Interface, error) {
+	return getSubInterface(ev.retry, eventErrors.retry)
+}
+
+func (ev *event) Cron() (cron.Interface, error) {
+	return getSubInterface(ev.cron, eventErrors.cron)
+}
+
 func getSubInterface[T any](i T, err error) (T, error) {
 	if reflect.ValueOf(i).IsValid() && !reflect.ValueOf(i).IsZero() {
 		return i, nil
@@ -9,9 +9,12 @@ import (
 
 	"github.com/google/uuid"
 	"gitlab.com/YSX/eventloop/internal/loggerImplementation"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/cron"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/interval"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/once"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/retry"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
 	"gitlab.com/YSX/eventloop/pkg/logger"
 )
@@ -63,7 +66,8 @@ func deepEqual(one Interface, two Interface) bool {
 		isSame(one.After, two.After, "after") &&
 		isSame(one.Subscriber, two.Subscriber, "subscriber") &&
 		isSame(one.Interval, two.Interval, "interval") &&
-		isSame(one.Once, two.Once, "once") {
+		isSame(one.Once, two.Once, "once") &&
+		isSame(one.Cron, two.Cron, "cron") {
 		return true
 	}
 	return false
@@ -130,7 +134,7 @@ func TestNewEvent(t *testing.T) {
 			want: func(id string) Interface {
 				return &event{
 					uuid: id, fun: testData.F, triggerName: testData.TRIGGER, once: once.NewOnce(),
-					interval: interval.NewIntervalEvent(time.Minute),
+					interval: interval.NewIntervalEvent(time.Minute, interval.Options{}),
 				}
 			},
 		},
@@ -146,7 +150,8 @@ func TestNewEvent(t *testing.T) {
 			want: func(id string) Interface {
 				return &event{
 					uuid: id, fun: testData.F, triggerName: testData.TRIGGER, once: once.NewOnce(),
-					interval: interval.NewIntervalEvent(time.Minute), after: after.New(testData.Daa),
+					interval: interval.NewIntervalEvent(time.Minute, interval.Options{}),
+					after:    after.New(testData.Daa),
 				}
 			},
 		},
@@ -163,11 +168,40 @@ func TestNewEvent(t *testing.T) {
 			want: func(id string) Interface {
 				return &event{
 					uuid: id, fun: testData.F, triggerName: testData.TRIGGER, once: once.NewOnce(),
-					interval: interval.NewIntervalEvent(time.Minute), after: after.New(testData.Daa),
+					interval:   interval.NewIntervalEvent(time.Minute, interval.Options{}),
+					after:      after.New(testData.Daa),
 					subscriber: subscriber.NewTriggerEvent(),
 				}
 			},
 		},
+		{
+			name: "Cron",
+			args: Args{Fun: testData.F, Cron: cron.Args{Expression: "@hourly"}},
+			want: func(id string) Interface {
+				cronComponent, _ := cron.New(cron.Args{Expression: "@hourly"})
+				return &event{uuid: id, fun: testData.F, cron: cronComponent}
+			},
+		},
+		{
+			name:    "Bad cron expression",
+			args:    Args{Fun: testData.F, Cron: cron.Args{Expression: "61 * * * *"}},
+			wantErr: true,
+		},
+		{
+			name:    "Bad misfire policy",
+			args:    Args{Fun: testData.F, TriggerName: testData.TRIGGER, Misfire: Misfire{Policy: "LATER"}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative interval",
+			args:    Args{Fun: testData.F, TriggerName: testData.TRIGGER, IntervalTime: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "Reserved trigger name",
+			args:    Args{Fun: testData.F, TriggerName: "$acme." + testData.TRIGGER},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(
@@ -344,7 +378,9 @@ func Test_event_GetTypes(t *testing.T) {
 		interval    interval.Interface
 		once        once.Interface
 		after       after.Interface
+		cron        cron.Interface
 	}
+	hourly, _ := cron.New(cron.Args{Expression: "@hourly"})
 	tests := []struct {
 		name    string
 		fields  fields
@@ -355,11 +391,12 @@ func Test_event_GetTypes(t *testing.T) {
 			fields: fields{
 				triggerName: testData.TRIGGER,
 				subscriber:  subscriber.NewSubscriberEvent(),
-				interval:    interval.NewIntervalEvent(time.Second),
+				interval:    interval.NewIntervalEvent(time.Second, interval.Options{}),
 				once:        once.NewOnce(),
 				after:       after.New(after.Args{Date: time.Now()}),
+				cron:        hourly,
 			},
-			wantOut: []Type{"TRIGGER", "ONCE", "AFTER", "INTERVAL", "SUBSCRIBER"},
+			wantOut: []Type{"TRIGGER", "ONCE", "AFTER", "INTERVAL", "SUBSCRIBER", "CRON"},
 		},
 		{
 			name:    "No types",
@@ -376,6 +413,7 @@ func Test_event_GetTypes(t *testing.T) {
 					interval:    tt.fields.interval,
 					once:        tt.fields.once,
 					after:       tt.fields.after,
+					cron:        tt.fields.cron,
 				}
 				if gotOut := ev.GetTypes(); !reflect.DeepEqual(gotOut, tt.wantOut) {
 					t.Errorf("GetTypes() = %v, want %v", gotOut, tt.wantOut)
@@ -386,7 +424,7 @@ func Test_event_GetTypes(t *testing.T) {
 }
 
 func Test_event_Interval(t *testing.T) {
-	var newInterval = interval.NewIntervalEvent(time.Minute)
+	var newInterval = interval.NewIntervalEvent(time.Minute, interval.Options{})
 	type fields struct {
 		interval interval.Interface
 	}
@@ -466,23 +504,130 @@ func Test_event_Once(t *testing.T) {
 	}
 }
 
+func Test_event_Retry(t *testing.T) {
+	var policy = retry.New(retry.Args{MaxAttempts: 3, Delay: time.Millisecond})
+	type fields struct {
+		retry retry.Interface
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    retry.Interface
+		wantErr bool
+	}{
+		{
+			name:   "With retry",
+			fields: fields{policy},
+			want:   policy,
+		},
+		{
+			name:    "No retry",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ev := &event{
+					retry: tt.fields.retry,
+				}
+				got, err := ev.Retry()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Retry() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Retry() got = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_event_Cron(t *testing.T) {
+	var hourly, _ = cron.New(cron.Args{Expression: "@hourly"})
+	tests := []struct {
+		name    string
+		cron    cron.Interface
+		want    cron.Interface
+		wantErr bool
+	}{
+		{
+			name: "With cron",
+			cron: hourly,
+			want: hourly,
+		},
+		{
+			name:    "No cron",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ev := &event{cron: tt.cron}
+				got, err := ev.Cron()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Cron() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Cron() got = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_event_SetClock(t *testing.T) {
+	var (
+		now   = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+		fake  = clock.NewFake(now)
+		ev, _ = NewEvent(
+			Args{
+				Fun: testData.F, TriggerName: testData.TRIGGER,
+				DateAfter: after.Args{Date: now.Add(time.Hour).AddDate(1, 1, 1)},
+			},
+		)
+	)
+	ev.SetClock(fake)
+
+	afterComponent, _ := ev.After()
+	if got := afterComponent.GetDuration(); got != time.Hour {
+		t.Errorf("After().GetDuration() = %v, want %v", got, time.Hour)
+	}
+	got := ev.RunFunction(logger.WithLogger(context.Background(), logger.NewNop()))
+	if !got.Start.Equal(now) || !got.End.Equal(now) {
+		t.Errorf("RunFunction() start = %v, end = %v, want %v", got.Start, got.End, now)
+	}
+}
+
 func Test_event_RunFunction(t *testing.T) {
 	var (
 		lgger, _ = loggerImplementation.NewLogger("DEBUG", "logs", "test")
 		ctx      = logger.WithLogger(context.Background(), lgger)
+		errFun   = errors.New("function error")
 	)
 	type fields struct {
 		fun        Func
+		errFun     ErrFunc
+		payloadFun PayloadFunc
 		subscriber subscriber.Interface
+		timeout    time.Duration
 	}
 	type args struct {
 		ctx context.Context
 	}
 	tests := []struct {
-		name       string
-		fields     fields
-		args       args
-		needHelper bool
+		name        string
+		fields      fields
+		args        args
+		needHelper  bool
+		wantValue   string
+		wantStatus  Status
+		wantErr     bool
+		wantPanic   bool
+		wantTimeout bool
 	}{
 		{
 			name: "With function",
@@ -491,7 +636,9 @@ func Test_event_RunFunction(t *testing.T) {
 					return "OK"
 				},
 			},
-			args: args{ctx},
+			args:       args{ctx},
+			wantValue:  "OK",
+			wantStatus: StatusDone,
 		},
 		{
 			name: "Subscriber",
@@ -503,6 +650,94 @@ func Test_event_RunFunction(t *testing.T) {
 			},
 			args:       args{ctx},
 			needHelper: true,
+			wantValue:  "OK",
+			wantStatus: StatusDone,
+		},
+		{
+			name: "Error function",
+			fields: fields{
+				errFun: func(ctx context.Context) (string, error) {
+					return "FAIL", errFun
+				},
+			},
+			args:       args{ctx},
+			wantValue:  "FAIL",
+			wantStatus: StatusFailed,
+			wantErr:    true,
+		},
+		{
+			name: "Payload function",
+			fields: fields{
+				payloadFun: func(ctx context.Context, payload any) (string, error) {
+					return payload.(string), nil
+				},
+			},
+			args:       args{WithPayload(ctx, "DATA")},
+			wantValue:  "DATA",
+			wantStatus: StatusDone,
+		},
+		{
+			name: "Panic",
+			fields: fields{
+				fun: func(ctx context.Context) string {
+					panic("PANIC")
+				},
+			},
+			args:       args{ctx},
+			wantStatus: StatusFailed,
+			wantErr:    true,
+			wantPanic:  true,
+		},
+		{
+			name: "Stop chain",
+			fields: fields{
+				errFun: func(ctx context.Context) (string, error) {
+					return Stop("INVALID")
+				},
+			},
+			args:       args{ctx},
+			wantValue:  "INVALID",
+			wantStatus: StatusHalted,
+		},
+		{
+			name: "In time",
+			fields: fields{
+				fun: func(ctx context.Context) string {
+					return "OK"
+				},
+				timeout: time.Second,
+			},
+			args:       args{ctx},
+			wantValue:  "OK",
+			wantStatus: StatusDone,
+		},
+		{
+			name: "Timeout",
+			fields: fields{
+				fun: func(ctx context.Context) string {
+					<-ctx.Done()
+					return "LATE"
+				},
+				timeout: 10 * time.Millisecond,
+			},
+			args:        args{ctx},
+			wantStatus:  StatusTimeout,
+			wantErr:     true,
+			wantTimeout: true,
+		},
+		{
+			name: "Timeout, function ignores context",
+			fields: fields{
+				fun: func(ctx context.Context) string {
+					time.Sleep(time.Second)
+					return "LATE"
+				},
+				timeout: 10 * time.Millisecond,
+			},
+			args:        args{ctx},
+			wantStatus:  StatusTimeout,
+			wantErr:     true,
+			wantTimeout: true,
 		},
 	}
 	for _, tt := range tests {
@@ -510,15 +745,35 @@ func Test_event_RunFunction(t *testing.T) {
 			tt.name, func(t *testing.T) {
 				ev := &event{
 					fun:        tt.fields.fun,
+					errFun:     tt.fields.errFun,
+					payloadFun: tt.fields.payloadFun,
 					subscriber: tt.fields.subscriber,
+					timeout:    tt.fields.timeout,
+					clock:      clock.New(),
 				}
 				if tt.needHelper {
+					sub, _ := ev.Subscriber()
+					sub.SetIsRunning(true)
 					go func() {
-						sub, _ := ev.Subscriber()
 						<-sub.ChanTrigger()
 					}()
 				}
-				ev.RunFunction(tt.args.ctx)
+				got := ev.RunFunction(tt.args.ctx)
+				if got.Value != tt.wantValue || got.Status != tt.wantStatus || (got.Err != nil) != tt.wantErr {
+					t.Errorf("RunFunction() = %+v, want value %v, status %v", got, tt.wantValue, tt.wantStatus)
+				}
+				var panicErr *PanicError
+				if errors.As(got.Err, &panicErr) != tt.wantPanic {
+					t.Errorf("RunFunction() error = %v, want panic %v", got.Err, tt.wantPanic)
+				}
+				var timeoutErr *TimeoutError
+				if errors.As(got.Err, &timeoutErr) != tt.wantTimeout ||
+					errors.Is(got.Err, context.DeadlineExceeded) != tt.wantTimeout {
+					t.Errorf("RunFunction() error = %v, want timeout %v", got.Err, tt.wantTimeout)
+				}
+				if tt.wantTimeout && timeoutErr.Done == nil {
+					t.Error("RunFunction() TimeoutError.Done = nil, want channel of running function")
+				}
 			},
 		)
 	}
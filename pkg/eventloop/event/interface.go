@@ -2,10 +2,14 @@ package event
 
 import (
 	"context"
+	"time"
 
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/cron"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/interval"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/once"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/retry"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
 )
 
@@ -14,10 +18,23 @@ type Interface interface {
 	GetPriority() int
 	GetPriorityString() string
 	GetTriggerName() string
-	RunFunction(ctx context.Context)
+	// GetNamespace возвращает пространство имён события, "" - общее
+	GetNamespace() string
+	GetTimeout() time.Duration
+	GetMisfire() Misfire
+	SetClock(c clock.Interface)
+	// SetPaused ставит событие на паузу или снимает с неё. Функция события на паузе не выполняется, но расписание
+	// интервалов и cron идёт дальше
+	SetPaused(paused bool)
+	IsPaused() bool
+	RunFunction(ctx context.Context) Result
+	// GetResult возвращает значение, которое функция события вернула при последнем выполнении
+	GetResult() string
 	After() (after.Interface, error)
 	Subscriber() (subscriber.Interface, error)
 	Interval() (interval.Interface, error)
 	Once() (once.Interface, error)
+	Retry() (retry.Interface, error)
+	Cron() (cron.Interface, error)
 	GetTypes() (out []Type)
 }
@@ -7,4 +7,17 @@ type Interface interface {
 	GetQuitChannel() chan bool
 	IsRunning() bool
 	SetRunning(run bool)
+	// StartRun отмечает начало выполнения функции на тике. false - тик нужно пропустить по политике OverlapSkip
+	StartRun() bool
+	// FinishRun отмечает конец выполнения, начатого через StartRun
+	FinishRun()
+	// AutoStart - запускать интервал при регистрации события (Options.AutoStart)
+	AutoStart() bool
+	// FirstRun - время первого запуска интервала, стартовавшего в start, с учётом Options.InitialDelay и Options.Align
+	FirstRun(start time.Time) time.Time
+	// Jitter - случайная задержка очередного запуска по Options.Jitter
+	Jitter() time.Duration
+	// TakeRuns отмечает n запусков функции. Возвращает, сколько из них укладывается в Options.MaxRuns, и true, если
+	// после них лимит исчерпан и событие пора завершать
+	TakeRuns(n int) (int, bool)
 }
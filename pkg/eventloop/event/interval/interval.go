@@ -1,19 +1,83 @@
 package interval
 
 import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// OverlapPolicy - что делать с тиком, если функция события с прошлого тика ещё выполняется
+type OverlapPolicy string
+
+const (
+	// OverlapAllow - запускать функцию на каждом тике, даже если предыдущий запуск не закончился (по умолчанию)
+	OverlapAllow OverlapPolicy = "ALLOW"
+	// OverlapSkip - пропускать тики, пока предыдущий запуск не закончился
+	OverlapSkip OverlapPolicy = "SKIP"
+)
+
+// Options - дополнительные настройки интервального события
+type Options struct {
+	Overlap OverlapPolicy
+	// Jitter - каждый запуск откладывается на случайное время от 0 до Jitter, чтобы запущенные вместе интервалы не
+	// срабатывали одновременно. Расписание от этого не сдвигается
+	Jitter time.Duration
+	// InitialDelay - первый запуск через InitialDelay после старта интервала, а не через интервал
+	InitialDelay time.Duration
+	// Align - первый запуск на ближайшей границе, кратной Align (time.Minute - в начале минуты), не раньше
+	// InitialDelay после старта. Следующие запуски - через интервал от неё
+	Align time.Duration
+	// MaxRuns - после стольких запусков функции событие завершается и удаляется из менеджера. 0 - без ограничения
+	MaxRuns int
+	// AutoStart - интервал запускается сразу при регистрации события, без триггера и StartInterval
+	AutoStart bool
+}
+
 // component - событие, запускаемое с определённым интервалом. Имеет собственный канал, с помощью которого можно
 // прервать работу события.
 type component struct {
 	interval  time.Duration
-	isRunning bool
+	isRunning atomic.Bool
 	quit      chan bool
+
+	overlap OverlapPolicy
+	// active - количество выполняющихся сейчас запусков функции
+	active int32
+
+	jitter       time.Duration
+	initialDelay time.Duration
+	align        time.Duration
+	maxRuns      int
+	autoStart    bool
+
+	mx sync.Mutex
+	// runs - сколько запусков уже отдано через TakeRuns
+	runs int
 }
 
-func NewIntervalEvent(interval time.Duration) Interface {
-	return &component{interval: interval, quit: make(chan bool)}
+func NewIntervalEvent(interval time.Duration, options Options) Interface {
+	if options.Overlap == "" {
+		options.Overlap = OverlapAllow
+	}
+	return &component{
+		interval:     interval,
+		overlap:      options.Overlap,
+		jitter:       nonNegative(options.Jitter),
+		initialDelay: nonNegative(options.InitialDelay),
+		align:        nonNegative(options.Align),
+		maxRuns:      options.MaxRuns,
+		autoStart:    options.AutoStart,
+		// Сигнал остановки не теряется, даже если горутина события ещё не дошла до ожидания
+		quit: make(chan bool, 1),
+	}
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
 }
 
 func (e *component) GetDuration() time.Duration {
@@ -25,9 +89,59 @@ func (e *component) GetQuitChannel() chan bool {
 }
 
 func (e *component) IsRunning() bool {
-	return e.isRunning
+	return e.isRunning.Load()
 }
 
 func (e *component) SetRunning(run bool) {
-	e.isRunning = run
+	e.isRunning.Store(run)
+}
+
+func (e *component) StartRun() bool {
+	if e.overlap == OverlapSkip {
+		return atomic.CompareAndSwapInt32(&e.active, 0, 1)
+	}
+	atomic.AddInt32(&e.active, 1)
+	return true
+}
+
+func (e *component) FinishRun() {
+	atomic.AddInt32(&e.active, -1)
+}
+
+func (e *component) AutoStart() bool {
+	return e.autoStart
+}
+
+func (e *component) FirstRun(start time.Time) time.Time {
+	if e.align <= 0 {
+		if e.initialDelay > 0 {
+			return start.Add(e.initialDelay)
+		}
+		return start.Add(e.interval)
+	}
+	first := start.Add(e.initialDelay)
+	if aligned := first.Truncate(e.align); aligned.Before(first) {
+		return aligned.Add(e.align)
+	}
+	return first
+}
+
+func (e *component) Jitter() time.Duration {
+	if e.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(e.jitter)))
+}
+
+func (e *component) TakeRuns(n int) (int, bool) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.maxRuns <= 0 {
+		return n, false
+	}
+	if left := e.maxRuns - e.runs; n > left {
+		n = left
+	}
+	e.runs += n
+	return n, e.runs >= e.maxRuns
 }
@@ -20,7 +20,7 @@ func TestNewIntervalEvent(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewIntervalEvent(tt.interval); got.IsRunning() != tt.want.IsRunning() ||
+			if got := NewIntervalEvent(tt.interval, Options{}); got.IsRunning() != tt.want.IsRunning() ||
 				got.GetDuration() != tt.want.GetDuration() {
 				t.Errorf("NewIntervalEvent() = %v, want %v", got, tt.want)
 			}
@@ -53,10 +53,10 @@ func Test_eventSchedule_GetDuration(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &component{
-				interval:  tt.fields.interval,
-				isRunning: tt.fields.isRunning,
-				quit:      tt.fields.quit,
+				interval: tt.fields.interval,
+				quit:     tt.fields.quit,
 			}
+			e.isRunning.Store(tt.fields.isRunning)
 			if got := e.GetDuration(); got != tt.want {
 				t.Errorf("GetDuration() = %v, want %v", got, tt.want)
 			}
@@ -90,10 +90,10 @@ func Test_eventSchedule_GetQuitChannel(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &component{
-				interval:  tt.fields.interval,
-				isRunning: tt.fields.isRunning,
-				quit:      tt.fields.quit,
+				interval: tt.fields.interval,
+				quit:     tt.fields.quit,
 			}
+			e.isRunning.Store(tt.fields.isRunning)
 			if got := e.GetQuitChannel(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetQuitChannel() = %v, want %v", got, tt.want)
 			}
@@ -121,10 +121,10 @@ func Test_eventSchedule_IsRunning(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &component{
-				interval:  tt.fields.interval,
-				isRunning: tt.fields.isRunning,
-				quit:      tt.fields.quit,
+				interval: tt.fields.interval,
+				quit:     tt.fields.quit,
 			}
+			e.isRunning.Store(tt.fields.isRunning)
 			if got := e.IsRunning(); got != tt.want {
 				t.Errorf("IsRunning() = %v, want %v", got, tt.want)
 			}
@@ -152,10 +152,10 @@ func Test_eventSchedule_SetRunning(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &component{
-				interval:  tt.fields.interval,
-				isRunning: tt.fields.isRunning,
-				quit:      tt.fields.quit,
+				interval: tt.fields.interval,
+				quit:     tt.fields.quit,
 			}
+			e.isRunning.Store(tt.fields.isRunning)
 			e.SetRunning(tt.want)
 			if got := e.IsRunning(); got != tt.want {
 				t.Errorf("IsRunning() = %v, want %v", got, tt.want)
@@ -163,3 +163,168 @@ func Test_eventSchedule_SetRunning(t *testing.T) {
 		})
 	}
 }
+
+func Test_component_StartRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		runs    int
+		want    bool
+	}{
+		{
+			name: "Allow, no active runs",
+			want: true,
+		},
+		{
+			name: "Allow, active run",
+			runs: 1,
+			want: true,
+		},
+		{
+			name:    "Skip, no active runs",
+			options: Options{Overlap: OverlapSkip},
+			want:    true,
+		},
+		{
+			name:    "Skip, active run",
+			options: Options{Overlap: OverlapSkip},
+			runs:    1,
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewIntervalEvent(time.Second, tt.options)
+			for i := 0; i < tt.runs; i++ {
+				e.StartRun()
+			}
+			if got := e.StartRun(); got != tt.want {
+				t.Errorf("StartRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_component_FinishRun(t *testing.T) {
+	e := NewIntervalEvent(time.Second, Options{Overlap: OverlapSkip})
+	if !e.StartRun() {
+		t.Fatalf("StartRun() = false, want true")
+	}
+	e.FinishRun()
+	if !e.StartRun() {
+		t.Errorf("StartRun() after FinishRun() = false, want true")
+	}
+}
+
+func Test_component_FirstRun(t *testing.T) {
+	start := time.Date(2023, 3, 10, 12, 0, 20, 0, time.UTC)
+	tests := []struct {
+		name    string
+		options Options
+		want    time.Time
+	}{
+		{
+			name: "Default",
+			want: start.Add(time.Minute),
+		},
+		{
+			name:    "Initial delay",
+			options: Options{InitialDelay: 5 * time.Second},
+			want:    start.Add(5 * time.Second),
+		},
+		{
+			name:    "Align",
+			options: Options{Align: time.Minute},
+			want:    time.Date(2023, 3, 10, 12, 1, 0, 0, time.UTC),
+		},
+		{
+			name:    "Align after initial delay",
+			options: Options{Align: 30 * time.Second, InitialDelay: 15 * time.Second},
+			want:    time.Date(2023, 3, 10, 12, 1, 0, 0, time.UTC),
+		},
+		{
+			name:    "Already aligned",
+			options: Options{Align: 10 * time.Second},
+			want:    start,
+		},
+		{
+			name:    "Negative initial delay",
+			options: Options{InitialDelay: -time.Second},
+			want:    start.Add(time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewIntervalEvent(time.Minute, tt.options).FirstRun(start); !got.Equal(tt.want) {
+				t.Errorf("FirstRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_component_Jitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter time.Duration
+	}{
+		{
+			name: "No jitter",
+		},
+		{
+			name:   "Jitter",
+			jitter: time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewIntervalEvent(time.Minute, Options{Jitter: tt.jitter})
+			for i := 0; i < 100; i++ {
+				if got := e.Jitter(); got < 0 || got > tt.jitter || (tt.jitter > 0 && got == tt.jitter) {
+					t.Fatalf("Jitter() = %v, want [0, %v)", got, tt.jitter)
+				}
+			}
+		})
+	}
+}
+
+func Test_component_TakeRuns(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxRuns int
+		// takes - сколько запусков просят по очереди
+		takes     []int
+		wantTaken []int
+		wantLast  []bool
+	}{
+		{
+			name:      "No limit",
+			takes:     []int{1, 3},
+			wantTaken: []int{1, 3},
+			wantLast:  []bool{false, false},
+		},
+		{
+			name:      "Limit reached",
+			maxRuns:   2,
+			takes:     []int{1, 1},
+			wantTaken: []int{1, 1},
+			wantLast:  []bool{false, true},
+		},
+		{
+			name:      "Missed runs cut by limit",
+			maxRuns:   3,
+			takes:     []int{1, 5},
+			wantTaken: []int{1, 2},
+			wantLast:  []bool{false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewIntervalEvent(time.Minute, Options{MaxRuns: tt.maxRuns})
+			for i, n := range tt.takes {
+				if taken, last := e.TakeRuns(n); taken != tt.wantTaken[i] || last != tt.wantLast[i] {
+					t.Errorf("TakeRuns(%v) = %v, %v, want %v, %v", n, taken, last, tt.wantTaken[i], tt.wantLast[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package event
+
+import (
+	"fmt"
+	"time"
+)
+
+// MisfirePolicy - что делать с запусками по расписанию (интервал, AFTER, cron), время которых прошло, пока процесс
+// стоял: машина спала, процесс был приостановлен или перегружен
+type MisfirePolicy string
+
+const (
+	// MisfireRunOnce - выполнить событие один раз сразу, сколько бы запусков ни было пропущено (по умолчанию)
+	MisfireRunOnce MisfirePolicy = "RUN_ONCE"
+	// MisfireRunAll - выполнить все пропущенные запуски подряд
+	MisfireRunAll MisfirePolicy = "RUN_ALL"
+	// MisfireSkip - пропущенные запуски не выполнять и ждать следующего по расписанию
+	MisfireSkip MisfirePolicy = "SKIP"
+)
+
+// DefaultMisfireThreshold - опоздание, после которого запуск считается пропущенным, если Misfire.Threshold не задан
+const DefaultMisfireThreshold = time.Second
+
+// Misfire - политика пропущенных запусков события
+type Misfire struct {
+	Policy MisfirePolicy
+	// Threshold - запуск, опоздавший меньше чем на Threshold, выполняется как обычно. По умолчанию
+	// DefaultMisfireThreshold
+	Threshold time.Duration
+}
+
+func newMisfire(misfire Misfire) (Misfire, error) {
+	if misfire.Policy == "" {
+		misfire.Policy = MisfireRunOnce
+	}
+	if misfire.Threshold <= 0 {
+		misfire.Threshold = DefaultMisfireThreshold
+	}
+	switch misfire.Policy {
+	case MisfireRunOnce, MisfireRunAll, MisfireSkip:
+		return misfire, nil
+	default:
+		return misfire, fmt.Errorf("unknown misfire policy %q", misfire.Policy)
+	}
+}
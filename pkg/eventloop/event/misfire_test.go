@@ -0,0 +1,44 @@
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_newMisfire(t *testing.T) {
+	tests := []struct {
+		name    string
+		misfire Misfire
+		want    Misfire
+		wantErr bool
+	}{
+		{
+			name:    "Default",
+			misfire: Misfire{},
+			want:    Misfire{Policy: MisfireRunOnce, Threshold: DefaultMisfireThreshold},
+		},
+		{
+			name:    "Custom",
+			misfire: Misfire{Policy: MisfireSkip, Threshold: time.Minute},
+			want:    Misfire{Policy: MisfireSkip, Threshold: time.Minute},
+		},
+		{
+			name:    "Unknown policy",
+			misfire: Misfire{Policy: "LATER"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := newMisfire(tt.misfire)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("newMisfire() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if err == nil && got != tt.want {
+					t.Errorf("newMisfire() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,37 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NamespacePrefix начинает внутреннее имя триггера пространства имён: $<namespace>.<triggerName>. Имена триггеров с
+// этим префиксом зарезервированы, иначе общий менеджер мог бы вызвать или изменить триггер чужого пространства
+const NamespacePrefix = "$"
+
+// ErrReservedTriggerName - имя триггера начинается с NamespacePrefix
+var ErrReservedTriggerName = errors.New("trigger name prefix " + NamespacePrefix + " is reserved for namespaces")
+
+// ValidateNamespace проверяет имя пространства имён: непустое, только буквы, цифры, '-' и '_'. Точки, шаблоны
+// триггеров и '/' запрещены, потому что имя пространства становится первым сегментом имени триггера и частью пути API
+func ValidateNamespace(namespace string) error {
+	if namespace == "" {
+		return fmt.Errorf("empty namespace")
+	}
+	for _, r := range namespace {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return fmt.Errorf("invalid namespace %q: unexpected %q", namespace, r)
+		}
+	}
+	return nil
+}
+
+// ValidateTriggerName проверяет, что имя триггера не начинается с зарезервированного NamespacePrefix
+func ValidateTriggerName(triggerName string) error {
+	if strings.HasPrefix(triggerName, NamespacePrefix) {
+		return fmt.Errorf("invalid trigger name %q: %w", triggerName, ErrReservedTriggerName)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		wantErr   bool
+	}{
+		{namespace: "acme"},
+		{namespace: "tenant_42-eu"},
+		{namespace: "", wantErr: true},
+		{namespace: "acme.eu", wantErr: true},
+		{namespace: "#", wantErr: true},
+		{namespace: "acme/eu", wantErr: true},
+		{namespace: "@SYSTEM", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.namespace, func(t *testing.T) {
+				if err := ValidateNamespace(tt.namespace); (err != nil) != tt.wantErr {
+					t.Errorf("ValidateNamespace() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestValidateTriggerName(t *testing.T) {
+	tests := []struct {
+		triggerName string
+		wantErr     bool
+	}{
+		{triggerName: "order.created"},
+		{triggerName: "order.$"},
+		{triggerName: "$acme.order.created", wantErr: true},
+		{triggerName: "$", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.triggerName, func(t *testing.T) {
+				err := ValidateTriggerName(tt.triggerName)
+				if (err != nil) != tt.wantErr || tt.wantErr && !errors.Is(err, ErrReservedTriggerName) {
+					t.Errorf("ValidateTriggerName() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
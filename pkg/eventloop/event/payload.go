@@ -0,0 +1,23 @@
+package event
+
+import "context"
+
+type payloadCtxKey struct{}
+
+// WithPayload кладёт в контекст данные триггера. Функция PayloadFunc получает их аргументом, а Func, ErrFunc и
+// middleware могут достать их через Payload или PayloadAs
+func WithPayload(ctx context.Context, payload any) context.Context {
+	return context.WithValue(ctx, payloadCtxKey{}, payload)
+}
+
+// Payload возвращает данные, переданные в TriggerWithPayload. Если данных нет, возвращается nil.
+// Событие-слушатель получает map[string]any, где ключ - UUID события-триггера, а значение - его данные.
+func Payload(ctx context.Context) any {
+	return ctx.Value(payloadCtxKey{})
+}
+
+// PayloadAs возвращает данные триггера, приведённые к типу T. Если данных нет или тип другой, ok = false
+func PayloadAs[T any](ctx context.Context) (result T, ok bool) {
+	result, ok = Payload(ctx).(T)
+	return
+}
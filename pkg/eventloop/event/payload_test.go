@@ -0,0 +1,71 @@
+package event
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want any
+	}{
+		{
+			name: "Default",
+			ctx:  WithPayload(context.Background(), map[string]any{"id": 1}),
+			want: map[string]any{"id": 1},
+		},
+		{
+			name: "No payload",
+			ctx:  context.Background(),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := Payload(tt.ctx); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Payload() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestPayloadAs(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "Default",
+			ctx:    WithPayload(context.Background(), "DATA"),
+			want:   "DATA",
+			wantOk: true,
+		},
+		{
+			name:   "Wrong type",
+			ctx:    WithPayload(context.Background(), 1),
+			wantOk: false,
+		},
+		{
+			name:   "No payload",
+			ctx:    context.Background(),
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, ok := PayloadAs[string](tt.ctx)
+				if got != tt.want || ok != tt.wantOk {
+					t.Errorf("PayloadAs() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,68 @@
+package event
+
+import "time"
+
+type Status string
+
+const (
+	// StatusDone - функция события выполнена
+	StatusDone Status = "DONE"
+	// StatusFailed - функция события вернула ошибку или запаниковала (тогда Err - *PanicError)
+	StatusFailed Status = "FAILED"
+	// StatusTimeout - функция события не уложилась в таймаут (Err - *TimeoutError)
+	StatusTimeout Status = "TIMEOUT"
+	// StatusHalted - функция события вернула ErrStop, оставшиеся события триггера не выполнялись
+	StatusHalted Status = "HALTED"
+	// StatusSkipped - событие не выполнялось, потому что цепочку триггера остановило событие с большим приоритетом
+	StatusSkipped Status = "SKIPPED"
+	// StatusMissed - запуск по расписанию опоздал и не выполнялся по политике MisfireSkip
+	StatusMissed Status = "MISSED"
+	// StatusDropped - событие не выполнялось, пул воркеров выкинул его из переполненной очереди
+	StatusDropped Status = "DROPPED"
+	// StatusRejected - событие не выполнялось, пул воркеров не принял его в переполненную очередь
+	StatusRejected Status = "REJECTED"
+	// StatusCancelled - событие не выполнялось, менеджер событий остановлен (Shutdown) или событие отменено
+	// (CancelEvent)
+	StatusCancelled Status = "CANCELLED"
+	// StatusPaused - событие не выполнялось, оно на паузе (PauseEvent)
+	StatusPaused Status = "PAUSED"
+	// StatusStarted - интервальное событие запущено
+	StatusStarted Status = "STARTED"
+	// StatusStopped - интервальное событие остановлено
+	StatusStopped Status = "STOPPED"
+	// StatusScheduled - AFTER событие ждёт своего времени, результат выполнения придёт в Result.Completion
+	StatusScheduled Status = "SCHEDULED"
+)
+
+// Result - результат одного выполнения события
+type Result struct {
+	UUID     string
+	Priority int
+	Status   Status
+	Value    string
+	Err      error
+	Start    time.Time
+	End      time.Time
+
+	// Attempts - все попытки выполнения, если у события есть политика повторов (event.Args.Retry)
+	Attempts []Attempt
+	// RetriesExhausted - все попытки по политике повторов потрачены, а функция так и не выполнилась без ошибки
+	RetriesExhausted bool
+	// LateBy - насколько запуск по расписанию (интервал, AFTER, cron) опоздал относительно назначенного времени
+	LateBy time.Duration
+	// Completion - у StatusScheduled: канал, в который придёт результат выполнения события, когда наступит его время
+	Completion <-chan Result
+}
+
+// Attempt - одна попытка выполнения функции события
+type Attempt struct {
+	Number int
+	Err    error
+	Start  time.Time
+	End    time.Time
+}
+
+// Duration возвращает время выполнения функции события
+func (r Result) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
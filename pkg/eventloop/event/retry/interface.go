@@ -0,0 +1,9 @@
+package retry
+
+import "time"
+
+type Interface interface {
+	MaxAttempts() int
+	Delay(attempt int) time.Duration
+	IsRetryable(err error) bool
+}
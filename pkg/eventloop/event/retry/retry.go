@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+type Backoff string
+
+const (
+	// Fixed - между попытками всегда Delay
+	Fixed Backoff = "FIXED"
+	// Exponential - задержка удваивается после каждой попытки, но не больше MaxDelay
+	Exponential Backoff = "EXPONENTIAL"
+)
+
+// Args - настройки повторного выполнения события, функция которого вернула ошибку
+type Args struct {
+	// MaxAttempts - сколько всего раз выполнять функцию, включая первый. Повторы включаются при MaxAttempts > 1
+	MaxAttempts int
+	Backoff     Backoff
+	// Delay - задержка перед второй попыткой
+	Delay time.Duration
+	// MaxDelay - потолок задержки для Exponential. 0 - без потолка
+	MaxDelay time.Duration
+	// Jitter - случайный разброс задержки в долях от неё (от 0 до 1): 0.1 - это ±10%
+	Jitter float64
+	// Retryable решает, стоит ли повторять после ошибки err. Если не задана, повторяем после любой ошибки
+	Retryable func(err error) bool
+}
+
+// component - политика повторов события
+type component struct {
+	args Args
+}
+
+func New(args Args) Interface {
+	if args.Backoff == "" {
+		args.Backoff = Fixed
+	}
+	if args.Jitter < 0 {
+		args.Jitter = 0
+	} else if args.Jitter > 1 {
+		args.Jitter = 1
+	}
+	return &component{args: args}
+}
+
+func (c *component) MaxAttempts() int {
+	return c.args.MaxAttempts
+}
+
+// Delay возвращает задержку после неудачной попытки attempt (нумерация с 1)
+func (c *component) Delay(attempt int) time.Duration {
+	delay := c.args.Delay
+	if c.args.Backoff == Exponential {
+		for i := 1; i < attempt; i++ {
+			if c.args.MaxDelay > 0 && delay >= c.args.MaxDelay {
+				break
+			}
+			// Переполнение
+			if delay > delay<<1 {
+				break
+			}
+			delay <<= 1
+		}
+		if c.args.MaxDelay > 0 && delay > c.args.MaxDelay {
+			delay = c.args.MaxDelay
+		}
+	}
+
+	if c.args.Jitter > 0 && delay > 0 {
+		spread := float64(delay) * c.args.Jitter
+		delay += time.Duration(spread * (2*rand.Float64() - 1))
+	}
+	return delay
+}
+
+func (c *component) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if c.args.Retryable == nil {
+		return true
+	}
+	return c.args.Retryable(err)
+}
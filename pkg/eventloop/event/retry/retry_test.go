@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_component_Delay(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    Args
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "Fixed",
+			args:    Args{MaxAttempts: 3, Delay: time.Second},
+			attempt: 3,
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+		{
+			name:    "Exponential",
+			args:    Args{MaxAttempts: 5, Backoff: Exponential, Delay: time.Second},
+			attempt: 4,
+			wantMin: 8 * time.Second,
+			wantMax: 8 * time.Second,
+		},
+		{
+			name:    "Exponential max delay",
+			args:    Args{MaxAttempts: 5, Backoff: Exponential, Delay: time.Second, MaxDelay: 5 * time.Second},
+			attempt: 4,
+			wantMin: 5 * time.Second,
+			wantMax: 5 * time.Second,
+		},
+		{
+			name:    "Jitter",
+			args:    Args{MaxAttempts: 3, Delay: time.Second, Jitter: 0.1},
+			attempt: 1,
+			wantMin: 900 * time.Millisecond,
+			wantMax: 1100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				c := New(tt.args)
+				if got := c.Delay(tt.attempt); got < tt.wantMin || got > tt.wantMax {
+					t.Errorf("Delay() = %v, want from %v to %v", got, tt.wantMin, tt.wantMax)
+				}
+			},
+		)
+	}
+}
+
+func Test_component_IsRetryable(t *testing.T) {
+	var errPermanent = errors.New("permanent")
+	tests := []struct {
+		name string
+		args Args
+		err  error
+		want bool
+	}{
+		{
+			name: "Any error",
+			args: Args{MaxAttempts: 2},
+			err:  errors.New("temporary"),
+			want: true,
+		},
+		{
+			name: "No error",
+			args: Args{MaxAttempts: 2},
+			want: false,
+		},
+		{
+			name: "Predicate",
+			args: Args{
+				MaxAttempts: 2, Retryable: func(err error) bool {
+					return !errors.Is(err, errPermanent)
+				},
+			},
+			err:  errPermanent,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := New(tt.args).IsRetryable(tt.err); got != tt.want {
+					t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -1,15 +1,23 @@
 package subscriber
 
+import "sync/atomic"
+
 type Interface interface {
 	LockMutex()
 	UnlockMutex()
-	AddChannel(eventUUID string, infoCh chan SubChInfo, b *bool)
+	AddChannel(eventUUID string, infoCh chan SubChInfo, closed *atomic.Bool)
+	RemoveChannel(eventUUID string) bool
 	Channels() channelsByUUIDString
-	ChanTrigger() chan struct{}
+	Links() channelsByUUIDString
+	Changed() chan struct{}
+	ChanTrigger() chan any
+	Notify(payload any) bool
 	Exit() chan struct{}
 	GetType() Type
 	IsRunning() bool
 	SetIsRunning(b bool)
+	StartRunning() bool
+	StopIfUnlinked() bool
 }
 
 type InterfaceSubChannels interface {
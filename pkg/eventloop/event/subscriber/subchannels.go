@@ -1,13 +1,14 @@
 package subscriber
 
 import (
-	"sync"
+	"sync/atomic"
 )
 
+// SubChannel - связь события-триггера со слушателем. isClosed общий для обеих сторон связи, поэтому он атомарный, а не
+// под мьютексом одной из сторон
 type SubChannel struct {
 	infoCh   chan SubChInfo
-	isClosed *bool
-	mx       sync.RWMutex
+	isClosed *atomic.Bool
 }
 
 func (sc *SubChannel) GetInfoCh() chan SubChInfo {
@@ -15,13 +16,9 @@ func (sc *SubChannel) GetInfoCh() chan SubChInfo {
 }
 
 func (sc *SubChannel) IsClosed() bool {
-	sc.mx.RLock()
-	defer sc.mx.RUnlock()
-	return *sc.isClosed
+	return sc.isClosed.Load()
 }
 
 func (sc *SubChannel) SetIsClosed() {
-	sc.mx.Lock()
-	defer sc.mx.Unlock()
-	*sc.isClosed = true
+	sc.isClosed.Store(true)
 }
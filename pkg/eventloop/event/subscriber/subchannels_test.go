@@ -2,7 +2,7 @@ package subscriber
 
 import (
 	"reflect"
-	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -10,8 +10,7 @@ func TestSubChannel_GetInfoCh(t *testing.T) {
 	var ch = make(chan SubChInfo)
 	type fields struct {
 		infoCh   chan SubChInfo
-		isClosed *bool
-		mx       sync.RWMutex
+		isClosed *atomic.Bool
 	}
 	tests := []struct {
 		name   string
@@ -24,12 +23,11 @@ func TestSubChannel_GetInfoCh(t *testing.T) {
 			want:   ch,
 		},
 	}
-	for _, tt := range tests { //nolint:govet
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sc := &SubChannel{
 				infoCh:   tt.fields.infoCh,
 				isClosed: tt.fields.isClosed,
-				mx:       tt.fields.mx, //nolint:govet
 			}
 			if got := sc.GetInfoCh(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetInfoCh() = %v, want %v", got, tt.want)
@@ -39,11 +37,11 @@ func TestSubChannel_GetInfoCh(t *testing.T) {
 }
 
 func TestSubChannel_IsCLosed(t *testing.T) {
-	var b = true
+	var b atomic.Bool
+	b.Store(true)
 	type fields struct {
 		infoCh   chan SubChInfo
-		isClosed *bool
-		mx       sync.RWMutex
+		isClosed *atomic.Bool
 	}
 	tests := []struct {
 		name   string
@@ -61,7 +59,6 @@ func TestSubChannel_IsCLosed(t *testing.T) {
 			sc := &SubChannel{
 				infoCh:   tt.fields.infoCh,
 				isClosed: tt.fields.isClosed,
-				mx:       tt.fields.mx,
 			}
 			if got := sc.IsClosed(); got != tt.want {
 				t.Errorf("IsClosed() = %v, want %v", got, tt.want)
@@ -71,11 +68,11 @@ func TestSubChannel_IsCLosed(t *testing.T) {
 }
 
 func TestSubChannel_SetIsClosed(t *testing.T) {
-	var b = true
+	var b atomic.Bool
+	b.Store(true)
 	type fields struct {
 		infoCh   chan SubChInfo
-		isClosed *bool
-		mx       sync.RWMutex
+		isClosed *atomic.Bool
 	}
 	tests := []struct {
 		name   string
@@ -88,12 +85,11 @@ func TestSubChannel_SetIsClosed(t *testing.T) {
 			want:   true,
 		},
 	}
-	for _, tt := range tests { //nolint:govet
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sc := &SubChannel{
 				infoCh:   tt.fields.infoCh,
 				isClosed: tt.fields.isClosed,
-				mx:       tt.fields.mx, //nolint:govet
 			}
 			sc.SetIsClosed()
 			if got := sc.IsClosed(); got != tt.want {
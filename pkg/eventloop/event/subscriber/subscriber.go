@@ -2,16 +2,23 @@ package subscriber
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
-type SubChInfo int
+type SubChInfoType int
+
+// SubChInfo - сообщение, которое событие-триггер отправляет событиям-слушателям. В Payload лежат данные триггера
+type SubChInfo struct {
+	Type    SubChInfoType
+	Payload any
+}
 
 type Type string
 
 type channelsByUUIDString map[string]InterfaceSubChannels
 
 const (
-	TriggerListener SubChInfo = 1
+	TriggerListener SubChInfoType = 1
 )
 
 const (
@@ -21,8 +28,13 @@ const (
 
 // component - событие, которое триггерит другие события и/или триггерится само по другим событиям.
 type component struct {
-	trigger   chan struct{}
+	trigger   chan any
 	isRunning bool
+	// stopped закрывается, когда горутина события останавливается, чтобы Notify не ждал её вечно
+	stopped chan struct{}
+	// changed - сигнал горутине события, что связи добавили или убрали
+	changed chan struct{}
+	runMx   sync.Mutex
 
 	channels channelsByUUIDString
 	exit     chan struct{}
@@ -32,14 +44,16 @@ type component struct {
 
 func NewSubscriberEvent() Interface {
 	return &component{channels: make(channelsByUUIDString),
-		exit:   make(chan struct{}),
-		esType: Listener}
+		exit:    make(chan struct{}),
+		changed: make(chan struct{}, 1),
+		esType:  Listener}
 }
 
 func NewTriggerEvent() Interface {
 	return &component{channels: make(channelsByUUIDString),
-		trigger: make(chan struct{}),
+		trigger: make(chan any),
 		exit:    make(chan struct{}),
+		changed: make(chan struct{}, 1),
 		esType:  Trigger}
 }
 
@@ -55,16 +69,76 @@ func (ev *component) Channels() channelsByUUIDString {
 	return ev.channels
 }
 
+// Links возвращает копию связей события, её можно читать, не держа мьютекс
+func (ev *component) Links() channelsByUUIDString {
+	ev.mx.Lock()
+	defer ev.mx.Unlock()
+	links := make(channelsByUUIDString, len(ev.channels))
+	for id, ch := range ev.channels {
+		links[id] = ch
+	}
+	return links
+}
+
 // AddChannel добавляет каналы, по которым тригеррится событие, и по этим же каналам событие-триггер триггерит
-// события слушатели.
-func (ev *component) AddChannel(eventUUID string, infoCh chan SubChInfo, b *bool) {
-	ev.channels[eventUUID] = &SubChannel{infoCh: infoCh, isClosed: b}
+// события слушатели. closed - признак закрытия связи, общий для обеих её сторон.
+func (ev *component) AddChannel(eventUUID string, infoCh chan SubChInfo, closed *atomic.Bool) {
+	ev.mx.Lock()
+	ev.channels[eventUUID] = &SubChannel{infoCh: infoCh, isClosed: closed}
+	ev.mx.Unlock()
+	ev.signal()
+}
+
+// RemoveChannel убирает связь с событием eventUUID и помечает её закрытой для обеих сторон. false - связи не было
+func (ev *component) RemoveChannel(eventUUID string) bool {
+	ev.mx.Lock()
+	ch, ok := ev.channels[eventUUID]
+	if ok {
+		ch.SetIsClosed()
+		delete(ev.channels, eventUUID)
+	}
+	ev.mx.Unlock()
+	if ok {
+		ev.signal()
+	}
+	return ok
 }
 
-func (ev *component) ChanTrigger() chan struct{} {
+// Changed - канал, в который приходит сигнал после AddChannel и RemoveChannel
+func (ev *component) Changed() chan struct{} {
+	return ev.changed
+}
+
+func (ev *component) signal() {
+	select {
+	case ev.changed <- struct{}{}:
+	default:
+	}
+}
+
+// ChanTrigger - канал, по которому событие-триггер передаёт свои данные горутине, оповещающей слушателей
+func (ev *component) ChanTrigger() chan any {
 	return ev.trigger
 }
 
+// Notify передаёт данные события-триггера его горутине. false - горутина не запущена или остановилась, не приняв их
+func (ev *component) Notify(payload any) bool {
+	ev.runMx.Lock()
+	if !ev.isRunning || ev.trigger == nil {
+		ev.runMx.Unlock()
+		return false
+	}
+	stopped := ev.stopped
+	ev.runMx.Unlock()
+
+	select {
+	case ev.trigger <- payload:
+		return true
+	case <-stopped:
+		return false
+	}
+}
+
 func (ev *component) Exit() chan struct{} {
 	return ev.exit
 }
@@ -74,9 +148,46 @@ func (ev *component) GetType() Type {
 }
 
 func (ev *component) IsRunning() bool {
+	ev.runMx.Lock()
+	defer ev.runMx.Unlock()
 	return ev.isRunning
 }
 
 func (ev *component) SetIsRunning(b bool) {
+	ev.runMx.Lock()
+	defer ev.runMx.Unlock()
+	ev.setIsRunning(b)
+}
+
+// StartRunning отмечает горутину события запущенной. false - она уже запущена, вторая не нужна
+func (ev *component) StartRunning() bool {
+	ev.runMx.Lock()
+	defer ev.runMx.Unlock()
+	if ev.isRunning {
+		return false
+	}
+	ev.setIsRunning(true)
+	return true
+}
+
+// StopIfUnlinked останавливает горутину события, если у него не осталось связей. Связи проверяются под тем же
+// мьютексом, что и в AddChannel, поэтому связь, добавленная одновременно, не останется без горутины
+func (ev *component) StopIfUnlinked() bool {
+	ev.mx.Lock()
+	defer ev.mx.Unlock()
+	if len(ev.channels) > 0 {
+		return false
+	}
+	ev.SetIsRunning(false)
+	return true
+}
+
+func (ev *component) setIsRunning(b bool) {
+	switch {
+	case b && !ev.isRunning:
+		ev.stopped = make(chan struct{})
+	case !b && ev.isRunning && ev.stopped != nil:
+		close(ev.stopped)
+	}
 	ev.isRunning = b
 }
@@ -3,7 +3,9 @@ package subscriber
 import (
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -21,8 +23,9 @@ func TestNewSubscriberEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got := NewSubscriberEvent()
 			tt.want = &component{channels: got.Channels(),
-				exit:   got.Exit(),
-				esType: Listener}
+				exit:    got.Exit(),
+				changed: got.Changed(),
+				esType:  Listener}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewSubscriberEvent() = %v, want %v", got, tt.want)
 			}
@@ -45,6 +48,7 @@ func TestNewTriggerEvent(t *testing.T) {
 			tt.want = &component{channels: got.Channels(),
 				trigger: got.ChanTrigger(),
 				exit:    got.Exit(),
+				changed: got.Changed(),
 				esType:  Trigger}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewTriggerEvent() = %v, want %v", got, tt.want)
@@ -54,9 +58,10 @@ func TestNewTriggerEvent(t *testing.T) {
 }
 
 func Test_eventSubscriber_AddChannel(t *testing.T) {
-	var b = true
+	var b atomic.Bool
+	b.Store(true)
 	type fields struct {
-		trigger  chan struct{}
+		trigger  chan any
 		channels channelsByUUIDString
 		exit     chan struct{}
 		mx       sync.Mutex
@@ -65,7 +70,7 @@ func Test_eventSubscriber_AddChannel(t *testing.T) {
 	type args struct {
 		eventID string
 		infoCh  chan SubChInfo
-		b       *bool
+		b       *atomic.Bool
 	}
 	tests := []struct {
 		name   string
@@ -90,7 +95,7 @@ func Test_eventSubscriber_AddChannel(t *testing.T) {
 			ev.AddChannel(tt.args.eventID, tt.args.infoCh, tt.args.b)
 
 			if got := ev.Channels()[tt.args.eventID]; got.GetInfoCh() != tt.args.infoCh || got.
-				IsClosed() != *tt.args.b {
+				IsClosed() != tt.args.b.Load() {
 				t.Errorf("Added channel inconsistent")
 			}
 		})
@@ -98,9 +103,9 @@ func Test_eventSubscriber_AddChannel(t *testing.T) {
 }
 
 func Test_eventSubscriber_ChanTrigger(t *testing.T) {
-	var trig = make(chan struct{})
+	var trig = make(chan any)
 	type fields struct {
-		trigger  chan struct{}
+		trigger  chan any
 		channels channelsByUUIDString
 		exit     chan struct{}
 		mx       sync.Mutex
@@ -109,7 +114,7 @@ func Test_eventSubscriber_ChanTrigger(t *testing.T) {
 	tests := []struct {
 		name   string
 		fields fields
-		want   chan struct{}
+		want   chan any
 	}{
 		{
 			name:   "Default",
@@ -140,12 +145,11 @@ func Test_eventSubscriber_ChanTrigger(t *testing.T) {
 func Test_eventSubscriber_Channels(t *testing.T) {
 	var (
 		id       = uuid.NewString()
-		b        = true
 		channels = channelsByUUIDString{id: &SubChannel{infoCh: make(chan SubChInfo),
-			isClosed: &b}}
+			isClosed: &atomic.Bool{}}}
 	)
 	type fields struct {
-		trigger  chan struct{}
+		trigger  chan any
 		channels channelsByUUIDString
 		exit     chan struct{}
 		mx       sync.Mutex
@@ -186,7 +190,7 @@ func Test_eventSubscriber_Channels(t *testing.T) {
 func Test_eventSubscriber_Exit(t *testing.T) {
 	var ch = make(chan struct{})
 	type fields struct {
-		trigger  chan struct{}
+		trigger  chan any
 		channels channelsByUUIDString
 		exit     chan struct{}
 		mx       sync.Mutex
@@ -226,7 +230,7 @@ func Test_eventSubscriber_Exit(t *testing.T) {
 
 func Test_eventSubscriber_GetType(t *testing.T) {
 	type fields struct {
-		trigger  chan struct{}
+		trigger  chan any
 		channels channelsByUUIDString
 		exit     chan struct{}
 		mx       sync.Mutex
@@ -271,7 +275,7 @@ func Test_eventSubscriber_GetType(t *testing.T) {
 
 func Test_eventSubscriber_LockMutex(t *testing.T) {
 	type fields struct {
-		trigger  chan struct{}
+		trigger  chan any
 		channels channelsByUUIDString
 		exit     chan struct{}
 		mx       sync.Mutex
@@ -300,3 +304,115 @@ func Test_eventSubscriber_LockMutex(t *testing.T) {
 		})
 	}
 }
+
+func Test_eventSubscriber_RemoveChannel(t *testing.T) {
+	var (
+		id = uuid.NewString()
+		b  atomic.Bool
+	)
+	tests := []struct {
+		name    string
+		eventID string
+		want    bool
+	}{
+		{
+			name:    "Linked",
+			eventID: id,
+			want:    true,
+		},
+		{
+			name:    "Not linked",
+			eventID: uuid.NewString(),
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b.Store(false)
+			ev := NewSubscriberEvent()
+			ev.AddChannel(id, make(chan SubChInfo), &b)
+			<-ev.Changed()
+
+			if got := ev.RemoveChannel(tt.eventID); got != tt.want {
+				t.Errorf("RemoveChannel() = %v, want %v", got, tt.want)
+			}
+			if _, linked := ev.Links()[id]; linked == tt.want || b.Load() != tt.want {
+				t.Errorf("link after RemoveChannel() linked = %v, closed = %v", linked, b.Load())
+			}
+			select {
+			case <-ev.Changed():
+				if !tt.want {
+					t.Error("Changed() signalled without changes")
+				}
+			default:
+				if tt.want {
+					t.Error("Changed() not signalled")
+				}
+			}
+		})
+	}
+}
+
+func Test_eventSubscriber_Notify(t *testing.T) {
+	tests := []struct {
+		name string
+		// run - горутина события: запускается и читает данные или останавливается, не читая их
+		run  func(ev Interface)
+		want bool
+	}{
+		{
+			name: "Not running",
+			want: false,
+		},
+		{
+			name: "Running",
+			run: func(ev Interface) {
+				ev.StartRunning()
+				go func() {
+					<-ev.ChanTrigger()
+				}()
+			},
+			want: true,
+		},
+		{
+			name: "Stopped while waiting",
+			run: func(ev Interface) {
+				ev.StartRunning()
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					ev.StopIfUnlinked()
+				}()
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := NewTriggerEvent()
+			if tt.run != nil {
+				tt.run(ev)
+			}
+			if got := ev.Notify("payload"); got != tt.want {
+				t.Errorf("Notify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_eventSubscriber_StartRunning(t *testing.T) {
+	var b atomic.Bool
+	ev := NewTriggerEvent()
+	if !ev.StartRunning() || ev.StartRunning() {
+		t.Error("StartRunning() must succeed only once")
+	}
+	ev.AddChannel(uuid.NewString(), make(chan SubChInfo), &b)
+	if ev.StopIfUnlinked() || !ev.IsRunning() {
+		t.Error("StopIfUnlinked() stopped linked event")
+	}
+	for id := range ev.Links() {
+		ev.RemoveChannel(id)
+	}
+	if !ev.StopIfUnlinked() || ev.IsRunning() {
+		t.Error("StopIfUnlinked() did not stop unlinked event")
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"gitlab.com/YSX/eventloop/internal/loggerImplementation"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/internal"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/scheduler"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/workerPool"
 	loggerEventLoop "gitlab.com/YSX/eventloop/pkg/logger"
 	"golang.org/x/exp/slices"
 )
@@ -32,89 +35,243 @@ type eventLoop struct {
 
 	disabled []EventFunction
 
+	errorHooks []ErrorHook
+	// middleware - обёртки выполнения всех событий (Use), triggerMiddleware - событий одного триггера (UseTrigger)
+	middleware        []Middleware
+	triggerMiddleware map[string][]Middleware
+
+	// triggerConfigs - настройки выполнения триггеров, заданные через ConfigureTrigger
+	triggerConfigs map[string]TriggerConfig
+	// triggerLimiters - debounce, throttle и ограничения частоты триггеров из triggerConfigs
+	triggerLimiters map[string]*triggerLimiter
+	// rateLimit - общее ограничение вызовов Trigger (WithRateLimit), rateLimiter - его жетоны
+	rateLimit   RateLimit
+	rateLimiter *tokenBucket
+	// quotas - квоты пространств имён, заданные через ConfigureNamespace
+	quotas map[string]Quota
+	// subscribers - события, связанные через Subscribe, по UUID. Триггеры могут быть не зарегистрированы, поэтому
+	// Unsubscribe ищет события здесь, а не в events
+	subscribers map[string]event.Interface
+
+	// pool - пул воркеров для выполнения функций событий, nil - без ограничений (см. WithWorkerPool)
+	pool workerPool.Interface
+	// spawned - запуски из функций часов, ждущие места в пуле (см. spawn)
+	spawned spawnQueue
+
+	clock clock.Interface
+	// scheduler - одна куча для всех таймеров и тикеров менеджера поверх clock, см. timers
+	scheduler clock.Interface
+
+	// intervalMx - чтобы один интервал не запустили дважды из Trigger, StartInterval и AutoStart одновременно
+	intervalMx sync.Mutex
+	// intervals - запущенные интервалы по UUID события, под intervalMx
+	intervals map[string]*intervalRun
+	// crons - запущенные cron события по UUID, под intervalMx
+	crons map[string]*cronRun
+
+	// done закрывается при Shutdown
+	done       chan struct{}
+	executions executions
+
 	logger loggerEventLoop.Interface
+	// initErr - ошибка опции, которую не удалось применить в NewEventLoop (см. InitError)
+	initErr error
 }
 
-// NewEventLoop - конструктор для менеджера событий. Инициализирует новый Event Loop.
-// Для level рекомендуются DebugLevel для Dev, и ErrorLevel для Prod. Можно указать любой уровень, он нормализуется в
-// Debug и Error, в зависимости от велчины уровня.
-func NewEventLoop(level string) Interface {
-	elLogger, err := loggerImplementation.NewLogger(level, "logs", "")
-	if err != nil {
-		fmt.Printf("logger init error: %v", err)
-	}
-	return &eventLoop{
+// NewEventLoop - конструктор для менеджера событий. Инициализирует новый Event Loop. Без опций логи никуда не пишутся,
+// события хранятся в памяти, время - настоящее. Логгер, часы, пул воркеров, хранилище событий, хуки ошибок и общее
+// ограничение частоты вызовов задаются опциями WithLogger, WithClock, WithWorkerPool, WithStorage, WithErrorHook и
+// WithRateLimit.
+func NewEventLoop(opts ...Option) Interface {
+	e := &eventLoop{
 		mx:     &sync.RWMutex{},
 		events: eventsContainer.New(),
-		logger: elLogger,
+		logger: loggerEventLoop.NewNop(),
+		clock:  clock.New(),
+		done:   make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.scheduler = scheduler.New(e.clock)
+	// Жетоны считаются по часам менеджера, поэтому корзина создаётся после всех опций
+	if e.rateLimit.Limit > 0 {
+		e.rateLimiter = newTokenBucket(Throttle(e.rateLimit), e.clock.Now())
+	}
+	return e
 }
 
+// timers возвращает часы, по которым заводятся таймеры и тикеры: ожидание AFTER событий, интервалы, cron и задержки
+// между повторами. Все они обслуживаются одной кучей планировщика, а не отдельным таймером рантайма на каждое событие
+func (e *eventLoop) timers() clock.Interface {
+	if e.scheduler == nil {
+		return e.clock
+	}
+	return e.scheduler
+}
+
+// InitError возвращает ошибку опции, которую не удалось применить в NewEventLoop. Менеджер событий при этом работает
+// без неё
+func (e *eventLoop) InitError() error {
+	return e.initErr
+}
+
+// NewEventLoopWithLevel - прежний конструктор: пишет логи в папку logs с уровнем level.
+// Для level рекомендуются DebugLevel для Dev, и ErrorLevel для Prod. Можно указать любой уровень, он нормализуется в
+// Debug и Error, в зависимости от велчины уровня.
+func NewEventLoopWithLevel(level string, opts ...Option) Interface {
+	return NewEventLoop(append([]Option{WithLogLevel(level)}, opts...)...)
+}
+
+// RegisterEvent регистрирует события. Перед регистрацией каждого события выполняются системные события BEFORE_CREATE,
+// которые могут её отменить, после - AFTER_CREATE (см. Lifecycle)
 func (e *eventLoop) RegisterEvent(
 	ctx context.Context,
 	newEvents ...event.Interface,
 ) (errReturn error) {
-	e.mx.Lock()
-	defer e.mx.Unlock()
 	for _, evnt := range newEvents {
-		if ctxErr := e.checkContext(
-			ctx, "can't register event, context is done",
-			"events", evnt.GetUUID(),
-			"trigger", evnt.GetTriggerName(),
-		); ctxErr != nil {
-			internal.WriteToExecCh(ctx, "")
-			errReturn = internal.WrapError(errReturn, ctxErr)
-			continue
+		// События жизненного цикла не вызываются для самих системных событий
+		hooks := !isSystemEvent(evnt)
+		if hooks {
+			if vetoErr := e.lifecycle(ctx, eventLifecycle(BEFORE_CREATE, evnt)); vetoErr != nil {
+				errReturn = internal.WrapError(errReturn, vetoErr)
+				continue
+			}
 		}
-		// Если выключено добавление - не добавляем
-		if slices.Contains(e.disabled, REGISTER) {
-			errStr := "register disabled, can't register event"
-			e.logger.Warnw(
-				errStr,
-				"event", evnt.GetUUID(),
-			)
-			internal.WriteToExecCh(ctx, "")
-			errReturn = internal.WrapError(errReturn, errors.New(errStr))
+		if err := e.registerEvent(ctx, evnt); err != nil {
+			errReturn = internal.WrapError(errReturn, err)
 			continue
 		}
 
-		// ON
-		if triggerName := evnt.GetTriggerName(); triggerName != "" {
-			e.events.AddEvent(evnt)
-			e.logger.Debugw(
-				"Event added", "triggerName", evnt.GetTriggerName(), "eventId",
-				evnt.GetUUID(),
-			)
-		} else if intervalComp, intervalErr := evnt.Interval(); intervalErr == nil { // INTERVAL
-			e.events.AddEvent(evnt)
-			e.logger.Debugw("Event added", "interval", intervalComp.GetDuration())
-		} else if afterComp, afterErr := evnt.After(); afterErr == nil { // AFTER
-			e.events.AddEvent(evnt)
-			e.logger.Debugw(
-				"Event added", "start_time", afterComp.GetDuration(),
-				"eventId",
-				evnt.GetUUID(),
-			)
-		} else {
-			errStr := "event must be at least ON, INTERVAL or AFTER"
-			errNew := fmt.Errorf(errStr)
-			e.logger.Debugw(errStr, "eventId", evnt.GetUUID())
-			errReturn = internal.WrapError(errReturn, errNew)
+		e.startCronEvent(evnt)
+		if intervalComp, intervalErr := evnt.Interval(); intervalErr == nil && intervalComp.AutoStart() {
+			e.startIntervalEvent(context.Background(), evnt)
+		}
+		if hooks {
+			_ = e.lifecycle(ctx, eventLifecycle(AFTER_CREATE, evnt))
+		}
+	}
+	return errReturn
+}
+
+// registerEvent проверяет и добавляет одно событие в контейнер
+func (e *eventLoop) registerEvent(ctx context.Context, evnt event.Interface) error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if err := e.checkRegister(ctx, evnt); err != nil {
+		return err
+	}
+
+	// Задержки и время выполнения события считаются по часам менеджера
+	evnt.SetClock(e.timers())
+
+	// ON
+	if triggerName := evnt.GetTriggerName(); triggerName != "" {
+		e.events.AddEvent(evnt)
+		e.logger.Debugw(
+			"Event added", "triggerName", evnt.GetTriggerName(), "eventId",
+			evnt.GetUUID(),
+		)
+	} else if intervalComp, intervalErr := evnt.Interval(); intervalErr == nil { // INTERVAL
+		e.events.AddEvent(evnt)
+		e.logger.Debugw("Event added", "interval", intervalComp.GetDuration())
+	} else if afterComp, afterErr := evnt.After(); afterErr == nil { // AFTER
+		e.events.AddEvent(evnt)
+		e.logger.Debugw(
+			"Event added", "start_time", afterComp.GetDuration(),
+			"eventId",
+			evnt.GetUUID(),
+		)
+	} else if cronComp, cronErr := evnt.Cron(); cronErr == nil { // CRON
+		e.events.AddEvent(evnt)
+		e.logger.Debugw("Event added", "cron", cronComp.Expression(), "eventId", evnt.GetUUID())
+	} else {
+		errStr := "event must be at least ON, INTERVAL, AFTER or CRON"
+		e.logger.Debugw(errStr, "eventId", evnt.GetUUID())
+		return fmt.Errorf(errStr)
+	}
+	return nil
+}
+
+// checkRegister проверяет, можно ли добавить событие в менеджер: контекст, Shutdown, выключенная регистрация и квоты
+// пространства имён. Вызывается под e.mx
+func (e *eventLoop) checkRegister(ctx context.Context, evnt event.Interface) error {
+	if ctxErr := e.checkContext(
+		ctx, "can't register event, context is done",
+		"events", evnt.GetUUID(),
+		"trigger", evnt.GetTriggerName(),
+	); ctxErr != nil {
+		return ctxErr
+	}
+	if e.isShutdown() {
+		e.logger.Warnw("can't register event, event loop is shut down", "event", evnt.GetUUID())
+		return ErrShutdown
+	}
+	// Если выключено добавление - не добавляем
+	if slices.Contains(e.disabled, REGISTER) {
+		errStr := "register disabled, can't register event"
+		e.logger.Warnw(
+			errStr,
+			"event", evnt.GetUUID(),
+		)
+		return errors.New(errStr)
+	}
+	if quotaErr := e.checkQuota(evnt); quotaErr != nil {
+		e.logger.Warnw("can't register event", "event", evnt.GetUUID(), "error", quotaErr)
+		return quotaErr
+	}
+	return nil
+}
+
+// registerListeners добавляет слушателей Subscribe в менеджер с теми же проверками, что и RegisterEvent. Добавляются
+// все слушатели или ни одного, уже добавленные прошлыми подписками пропускаются
+func (e *eventLoop) registerListeners(ctx context.Context, listeners []event.Interface) error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	added := make([]string, 0, len(listeners))
+	for _, listener := range listeners {
+		if e.isRegistered(listener) {
 			continue
 		}
+		if err := e.checkRegister(ctx, listener); err != nil {
+			e.events.RemoveEventByUUIDs(added...)
+			return err
+		}
+		listener.SetClock(e.timers())
+		e.events.AddEvent(listener)
+		added = append(added, listener.GetUUID())
+	}
+	return nil
+}
 
-		internal.WriteToExecCh(ctx, "")
+// isRegistered - есть ли событие в менеджере. Вызывается под e.mx
+func (e *eventLoop) isRegistered(ev event.Interface) bool {
+	for _, registered := range e.events.GetEventsByNamespace(ev.GetNamespace()) {
+		if registered.GetUUID() == ev.GetUUID() {
+			return true
+		}
 	}
-	return errReturn
+	return false
 }
 
 // Subscribe подписывает список событий listeners на список событий triggers. Само событие триггерится с помощью Trigger/
 // В случае передачи контекста с дедлайном или таймаутом, если контекст ещё живой, подписанные события всё равно
 // выполнятся один раз в случае триггера.
 func (e *eventLoop) Subscribe(ctx context.Context, triggers []event.Interface, listeners []event.Interface) error {
-	subCtx := loggerEventLoop.WithLogger(ctx, e.logger)
-
-	defer internal.WriteToExecCh(ctx, "")
+	if e.isShutdown() {
+		return ErrShutdown
+	}
+	// Горутины слушателей и триггеров живут, пока жив ctx, либо до Shutdown
+	subCtx, cancel := e.loopContext(loggerEventLoop.WithLogger(ctx, e.logger))
+	// runners - запущенные этим вызовом горутины и сам Subscribe. Последний из них отменяет контекст подписки, чтобы
+	// не держать горутину loopContext после остановки слушателей и триггеров
+	runners := int32(1)
+	done := func() {
+		if atomic.AddInt32(&runners, -1) == 0 {
+			cancel()
+		}
+	}
+	defer done()
 
 	if isContextDone(subCtx) {
 		errStr := "can't subscribe, context is done"
@@ -125,22 +282,44 @@ func (e *eventLoop) Subscribe(ctx context.Context, triggers []event.Interface, l
 		)
 		return errors.New(errStr)
 	}
+	if err := checkSubscribers(subscriber.Trigger, triggers); err != nil {
+		return err
+	}
+	if err := checkSubscribers(subscriber.Listener, listeners); err != nil {
+		return err
+	}
+	if err := e.registerListeners(subCtx, listeners); err != nil {
+		return err
+	}
+	e.rememberSubscribers(triggers, listeners)
 	for _, listener := range listeners {
 		listenerSubComponent, _ := listener.Subscriber()
 		for _, t := range triggers {
 			ch := make(chan subscriber.SubChInfo, 1)
-			generalClosedInfo := false
-			listenerSubComponent.AddChannel(t.GetUUID(), ch, &generalClosedInfo)
+			generalClosedInfo := &atomic.Bool{}
+			listenerSubComponent.AddChannel(t.GetUUID(), ch, generalClosedInfo)
 			e.logger.Infow("Event subscribed", "listenerSubComponent", t.GetUUID(), "listener", listener.GetUUID())
 			tSub, _ := t.Subscriber()
-			tSub.AddChannel(listener.GetUUID(), ch, &generalClosedInfo)
+			tSub.AddChannel(listener.GetUUID(), ch, generalClosedInfo)
+		}
+		// Запскаем ждуна для слушателя, когда триггеры сработают, и срабатываем сами. Горутина отмечается запущенной
+		// сразу, чтобы Trigger сразу после Subscribe не прошёл мимо неё
+		if listenerSubComponent.StartRunning() {
+			atomic.AddInt32(&runners, 1)
+			go func(listener event.Interface) {
+				defer done()
+				e.listenerLoop(subCtx, listener)
+			}(listener)
 		}
-		e.events.AddEvent(listener)
-		// Запскаем ждуна для слушателя, когда триггеры сработают, и срабатываем сами
-		go e.runnerListener(subCtx, listener)
 	}
 	for _, t := range triggers {
-		go e.runnerTrigger(subCtx, t)
+		if tSub, _ := t.Subscriber(); tSub.StartRunning() {
+			atomic.AddInt32(&runners, 1)
+			go func(t event.Interface) {
+				defer done()
+				e.triggerLoop(subCtx, t)
+			}(t)
+		}
 	}
 	return nil
 }
@@ -154,191 +333,317 @@ func isContextDone(ctx context.Context) bool {
 	}
 }
 
-// Горутина события-слушателя
-func (e *eventLoop) runnerListener(ctx context.Context, v event.Interface) {
-	var (
-		subComponent, _ = v.Subscriber()
-		channels        = subComponent.Channels()
-	)
-
-	if subComponent.IsRunning() {
-		return
-	}
-	subComponent.SetIsRunning(true)
-
-	exitChan := isEventDone(ctx, subComponent.Exit(), e.logger)
+// listenerLoop - горутина события-слушателя, уже отмеченная запущенной. Ждёт данные от всех связанных
+// событий-триггеров и выполняет функцию события. Останавливается, когда связей не осталось (см. Unsubscribe)
+func (e *eventLoop) listenerLoop(ctx context.Context, v event.Interface) {
+	subComponent, _ := v.Subscriber()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exitChan := isEventDone(runCtx, subComponent.Exit(), e.logger)
+	// Данные от каждого события-триггера, по UUID триггера
+	payloads := make(map[string]any)
 	for {
-		i := len(channels)
-		if i == 0 {
-			subComponent.SetIsRunning(false)
+		links := subComponent.Links()
+		if len(links) == 0 && subComponent.StopIfUnlinked() {
+			e.logger.Debugw("Subscriber event has no triggers, runner stopped", "event", v.GetUUID())
 			return
 		}
-		subComponent.LockMutex()
-		for id, ch := range channels {
-			select {
-			case <-exitChan:
-				for _, closeCh := range channels {
-					closeCh.SetIsClosed()
-				}
-				subComponent.SetIsRunning(false)
-				return
-			case <-ch.GetInfoCh():
-				if ch.IsClosed() {
-					delete(channels, id)
-				}
-				logTxt := fmt.Sprintf(
-					"Reading channel from %v [%v/%v]", id, i,
-					len(channels),
-				)
-				e.logger.Debugw(logTxt, "event", v.GetUUID())
-				i--
-			}
-			if i <= 0 {
-				if i < 0 {
-					panic("too much channels waited")
-				}
-				e.logger.Infow("Subscriber event fired", "event", v.GetUUID())
-				v.RunFunction(ctx)
+		id, link, waiting := pendingLink(links, payloads)
+		if !waiting {
+			e.logger.Infow("Subscriber event fired", "event", v.GetUUID())
+			e.handleResult(ctx, e.runEventFunction(event.WithPayload(ctx, payloads), v))
+			payloads = make(map[string]any, len(links))
+			continue
+		}
+
+		select {
+		case <-exitChan:
+			closeLinks(links)
+			subComponent.SetIsRunning(false)
+			return
+		case <-subComponent.Changed():
+			// Связи добавили или убрали - перечитываем их
+		case info := <-link.GetInfoCh():
+			payloads[id] = info.Payload
+			if link.IsClosed() {
+				subComponent.RemoveChannel(id)
 			}
+			logTxt := fmt.Sprintf("Reading channel from %v [%v/%v]", id, len(payloads), len(links))
+			e.logger.Debugw(logTxt, "event", v.GetUUID())
 		}
-		subComponent.UnlockMutex()
 	}
 }
 
-// Горутина события-триггера
-func (e *eventLoop) runnerTrigger(ctx context.Context, v event.Interface) {
-	var (
-		subComponent, _ = v.Subscriber()
-		channels        = subComponent.Channels()
-	)
-
-	if subComponent.IsRunning() {
-		return
+// pendingLink - связь, данных от которой слушатель ещё не получил. waiting == false - получены от всех
+func pendingLink(
+	links map[string]subscriber.InterfaceSubChannels,
+	payloads map[string]any,
+) (id string, link subscriber.InterfaceSubChannels, waiting bool) {
+	for id, link = range links {
+		if _, ok := payloads[id]; !ok {
+			return id, link, true
+		}
 	}
+	return "", nil, false
+}
 
-	subComponent.SetIsRunning(true)
+func closeLinks(links map[string]subscriber.InterfaceSubChannels) {
+	for _, link := range links {
+		link.SetIsClosed()
+	}
+}
 
-	exitChan := isEventDone(ctx, subComponent.Exit(), e.logger)
+// triggerLoop - горутина события-триггера, уже отмеченная запущенной. Передаёт данные триггера всем связанным
+// слушателям. Останавливается, когда связей не осталось (см. Unsubscribe)
+func (e *eventLoop) triggerLoop(ctx context.Context, v event.Interface) {
+	subComponent, _ := v.Subscriber()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exitChan := isEventDone(runCtx, subComponent.Exit(), e.logger)
 	e.logger.Debugw("Runner trigger started", "eventId", v.GetUUID())
 	for {
 		select {
 		case <-exitChan:
-			for _, closeCh := range channels {
-				closeCh.SetIsClosed()
-			}
+			closeLinks(subComponent.Links())
 			subComponent.SetIsRunning(false)
 			return
-		case <-subComponent.ChanTrigger():
+		case <-subComponent.Changed():
+		case payload := <-subComponent.ChanTrigger():
 			e.logger.Debugw("TriggerEvent activated", "eventId", v.GetUUID())
-			subComponent.LockMutex()
+			links := subComponent.Links()
 			i := 1
-			for id, chnl := range channels {
-				if chnl.IsClosed() {
-					delete(channels, id)
-				}
-				logTxt := fmt.Sprintf(
-					"Writing channel for %v [%v/%v]", id, i,
-					len(channels),
-				)
+			for id, link := range links {
+				logTxt := fmt.Sprintf("Writing channel for %v [%v/%v]", id, i, len(links))
 				e.logger.Debugw(logTxt, "event", v.GetUUID())
-				chnl.GetInfoCh() <- subscriber.TriggerListener
+				if !e.notifyListener(subComponent, id, link, payload, exitChan) {
+					closeLinks(subComponent.Links())
+					subComponent.SetIsRunning(false)
+					return
+				}
 				i++
 			}
-			subComponent.UnlockMutex()
+		}
+		if subComponent.StopIfUnlinked() {
+			e.logger.Debugw("Trigger event has no listeners, runner stopped", "eventId", v.GetUUID())
+			return
 		}
 	}
 }
 
+// notifyListener отправляет данные триггера слушателю id. Если связь убрали, пока слушатель не читал, отправка
+// пропускается. false - горутину триггера остановили
+func (e *eventLoop) notifyListener(
+	subComponent subscriber.Interface,
+	id string,
+	link subscriber.InterfaceSubChannels,
+	payload any,
+	exitChan <-chan struct{},
+) bool {
+	for !link.IsClosed() {
+		select {
+		case link.GetInfoCh() <- subscriber.SubChInfo{Type: subscriber.TriggerListener, Payload: payload}:
+			return true
+		case <-subComponent.Changed():
+		case <-exitChan:
+			return false
+		}
+	}
+	subComponent.RemoveChannel(id)
+	return true
+}
+
 // Trigger вызывает событие с определённым triggerName. Функция ждёт выполнения всех добавленных на событие функций,
 // поэтому синхронный вызов заблокирует родительский цикл выполнения программы.
-// В Ch пишется резульат выполнения каждого триггера, после использования канал закрывается. Поэтому для каждого вызова
-// нужно создавать новый channelEx
-func (e *eventLoop) Trigger(ctx context.Context, triggerName string) error {
-	errFunc := func(msg string) error {
+// Возвращает результаты выполнения каждого события триггера, отсортированные по приоритету. Интервальные события не
+// ждут своего выполнения, в результате для них только статус запуска или остановки. Если у триггера настроены debounce
+// или throttle (см. TriggerConfig), вызов может быть подавлен - тогда события не выполняются, а в результате
+// TriggerResult.Suppressed.
+func (e *eventLoop) Trigger(ctx context.Context, triggerName string) (TriggerResult, error) {
+	return e.TriggerWithPayload(ctx, triggerName, nil)
+}
+
+// TriggerWithPayload вызывает событие triggerName так же, как Trigger, и передаёт payload функциям всех событий
+// триггера, системным событиям BEFORE_TRIGGER и AFTER_TRIGGER и, через события-триггеры, подписанным слушателям.
+// Функция event.PayloadFunc получает данные аргументом, Func и ErrFunc - через event.Payload или event.PayloadAs.
+// Имена с префиксом event.NamespacePrefix зарезервированы за пространствами имён, их вызвать нельзя.
+func (e *eventLoop) TriggerWithPayload(ctx context.Context, triggerName string, payload any) (TriggerResult, error) {
+	if err := event.ValidateTriggerName(triggerName); err != nil {
+		e.logger.Warnw("can't trigger event", "eventname", triggerName, "error", err)
+		return TriggerResult{TriggerName: triggerName}, err
+	}
+	return e.triggerWithPayload(ctx, triggerName, payload)
+}
+
+// triggerWithPayload вызывает триггер по ключу контейнера, в том числе триггер пространства имён
+func (e *eventLoop) triggerWithPayload(ctx context.Context, triggerName string, payload any) (TriggerResult, error) {
+	result := TriggerResult{TriggerName: triggerName}
+
+	errFunc := func(msg string) (TriggerResult, error) {
 		e.logger.Warnw(
 			msg,
 			"eventname", triggerName,
 		)
-		internal.WriteToExecCh(ctx, "")
-		return errors.New(msg)
+		return result, errors.New(msg)
 	}
 
-	triggerCtx := loggerEventLoop.WithLogger(ctx, e.logger)
-
-	var deferErr error
+	triggerCtx := event.WithPayload(loggerEventLoop.WithLogger(ctx, e.logger), payload)
 
 	if ctxErr := e.checkContext(
 		triggerCtx,
 		"can't trigger event, context is done",
 		"triggerName", triggerName,
 	); ctxErr != nil {
-		internal.WriteToExecCh(ctx, "")
-		return ctxErr
+		return result, ctxErr
+	}
+
+	if errRate := e.checkRateLimit(triggerName); errRate != nil {
+		e.logger.Warnw("can't trigger event, rate limit exceeded", "eventname", triggerName, "error", errRate)
+		return result, errRate
+	}
+
+	// Debounce может придержать вызов до конца серии, поэтому события триггера берутся уже после него
+	suppressedCalls, suppressedBy, errSuppress := e.suppress(triggerCtx, triggerName)
+	if errSuppress != nil {
+		e.logger.Warnw("Debounced trigger call was not executed", "triggerName", triggerName, "error", errSuppress)
+		return result, errSuppress
+	}
+	if suppressedBy != "" {
+		e.logger.Debugw("Trigger call suppressed", "triggerName", triggerName, "by", suppressedBy)
+		result.Suppressed, result.SuppressedBy = true, suppressedBy
+		return result, nil
 	}
+	result.SuppressedCalls = suppressedCalls
 
+	e.logger.Debugw("Trying to get mutex", "triggerName", triggerName)
+	e.mx.RLock()
+	if e.isShutdown() {
+		e.mx.RUnlock()
+		e.logger.Warnw("can't trigger event, event loop is shut down", "eventname", triggerName)
+		return result, ErrShutdown
+	}
 	// Выключен ли Триггер
 	if slices.Contains(e.disabled, TRIGGER) {
+		e.mx.RUnlock()
 		return errFunc("can't trigger event, trigger function is disabled")
 	}
 
 	if !e.events.IsTriggerEnabled(triggerName) {
+		e.mx.RUnlock()
 		return errFunc("can't trigger event, trigger name is disabled")
 	}
 
-	e.logger.Debugw("Trying to get mutex", "triggerName", triggerName)
-	e.mx.Lock()
-	defer e.mx.Unlock()
+	var (
+		eventsByPriority = e.events.GetPrioritySortedEventsByTrigger(triggerName)
+		config           = e.triggerConfig(triggerName)
+	)
+	e.mx.RUnlock()
 
-	e.logger.Infow("ChanTrigger event", "triggerName", triggerName)
+	e.logger.Infow("ChanTrigger event", "triggerName", triggerName, "mode", config.Mode)
 
 	// Run before global events
-	e.triggerEventFuncList(triggerCtx, e.events.EventsByTrigger(string(BEFORE_TRIGGER))...)
-
-	eventsByPriority := e.events.GetPrioritySortedEventsByTrigger(triggerName)
-	for _, ev := range eventsByPriority {
-		e.logger.Debugw("Start runFunc goroutine", "eventId", ev.GetUUID())
-		go e.triggerEventFunc(triggerCtx, ev)
+	before := triggerLifecycle(BEFORE_TRIGGER, triggerName, eventsByPriority)
+	if vetoErr := e.lifecycle(triggerCtx, before); vetoErr != nil {
+		e.logger.Warnw("can't trigger event", "triggerName", triggerName, "error", vetoErr)
+		return result, vetoErr
+	}
 
-		if once, err := ev.Once(); err == nil {
-			once.Do(
-				func() {
-					e.RemoveEventByUUIDs(ev.GetUUID())
-				},
-			)
+	result.Events = make([]event.Result, len(eventsByPriority))
+	tiers := executionTiers(config.Mode, eventsByPriority)
+	for n, tier := range tiers {
+		e.runTier(triggerCtx, eventsByPriority, tier, result.Events)
+		if n == len(tiers)-1 {
+			break
+		}
+		if haltedBy, halted := haltingEvent(config, tier, result.Events); halted {
+			e.logger.Infow("Trigger chain halted", "triggerName", triggerName, "eventId", haltedBy)
+			result.HaltedBy = haltedBy
+			skipTiers(eventsByPriority, tiers[n+1:], result.Events)
+			break
 		}
-	}
-	if len(eventsByPriority) == 0 {
-		internal.WriteToExecCh(triggerCtx, "")
 	}
 
 	// Run after global events
-	e.triggerEventFuncList(triggerCtx, e.events.EventsByTrigger(string(AFTER_TRIGGER))...)
+	after := triggerLifecycle(AFTER_TRIGGER, triggerName, eventsByPriority)
+	after.TriggerResult = &result
+	_ = e.lifecycle(triggerCtx, after)
 
-	return deferErr
+	return result, nil
 }
 
-func (e *eventLoop) triggerEventFuncList(ctx context.Context, list ...event.Interface) {
-	for _, listItem := range list {
-		listItem.RunFunction(ctx)
+// triggerEventFunc выполняет событие триггера: интервал запускает или останавливает, функцию остальных событий
+// выполняет. lateBy - насколько опоздал запуск AFTER события, время которого дождался scheduleAfterEvent
+func (e *eventLoop) triggerEventFunc(ctx context.Context, ev event.Interface, lateBy time.Duration) event.Result {
+	if ev.IsPaused() {
+		return e.pausedResult(ev)
+	}
+
+	if _, err := ev.Interval(); err == nil {
+		result := event.Result{UUID: ev.GetUUID(), Priority: ev.GetPriority(), Start: e.clock.Now()}
+		if e.startIntervalEvent(ctx, ev) {
+			result.Status = event.StatusStarted
+		} else {
+			e.stopIntervalEvent(ev)
+			result.Status = event.StatusStopped
+		}
+		result.End = result.Start
+		return result
 	}
+	return e.runLate(ctx, ev, lateBy)
 }
 
-func (e *eventLoop) triggerEventFunc(ctx context.Context, ev event.Interface) {
-	if after, afterErr := ev.After(); afterErr == nil {
-		e.logger.Debugw("Waiting for start", "eventId", ev.GetUUID(), "time", after.GetDuration())
-		after.Wait()
+// scheduleAfterEvent ставит AFTER событие на часы менеджера и сразу возвращает StatusScheduled. Когда время
+// наступило, событие выполняется через spawn, а его результат проходит через хуки ошибок и приходит в
+// Result.Completion. Вызов триггера к этому времени уже вернулся, поэтому функция получает не его ctx, а контекст
+// менеджера событий с теми же данными триггера. Прерванное ожидание (CancelEvent, Shutdown) получает StatusCancelled
+func (e *eventLoop) scheduleAfterEvent(ctx context.Context, ev event.Interface) event.Result {
+	after, _ := ev.After()
+	if ev.IsPaused() {
+		return e.handleResult(ctx, e.pausedResult(ev))
 	}
 
-	if interval, err := ev.Interval(); err == nil {
-		if interval.IsRunning() {
-			interval.GetQuitChannel() <- true
-			internal.WriteToExecCh(ctx, "")
-		} else {
-			e.logger.Debugw("Run scheduled", "eventId", ev.GetUUID())
-			e.runScheduledEvent(ctx, ev)
+	var (
+		completion = make(chan event.Result, 1)
+		runCtx     = event.WithPayload(loggerEventLoop.WithLogger(context.Background(), e.logger), event.Payload(ctx))
+		finish     = func(ctx context.Context, result event.Result) event.Result {
+			result = e.handleResult(ctx, result)
+			completion <- result
+			return result
 		}
-	} else {
-		ev.RunFunction(ctx)
+	)
+	e.logger.Debugw("Waiting for start", "eventId", ev.GetUUID(), "time", after.GetDuration())
+	now := e.clock.Now()
+	scheduled := now.Add(after.GetDuration())
+	after.Schedule(
+		func(waited bool) {
+			if e.isShutdown() {
+				finish(runCtx, e.notExecutedResult(ev, ErrShutdown))
+				return
+			}
+			if !waited {
+				finish(runCtx, e.notExecutedResult(ev, ErrEventCancelled))
+				return
+			}
+
+			now := e.clock.Now()
+			runs := missedRuns(ev.GetMisfire(), scheduled, now, nil)
+			if len(runs) == 0 {
+				finish(runCtx, e.missedResult(ev, now.Sub(scheduled)))
+				return
+			}
+			e.spawn(
+				runCtx, func(ctx context.Context) event.Result {
+					loopCtx, cancel := e.loopContext(ctx)
+					defer cancel()
+					return finish(loopCtx, e.triggerEventFunc(loopCtx, ev, runs[0]))
+				}, func(err error) {
+					finish(runCtx, e.notExecutedResult(ev, err))
+				},
+			)
+		},
+	)
+	return event.Result{
+		UUID: ev.GetUUID(), Priority: ev.GetPriority(), Status: event.StatusScheduled, Start: now, End: now,
+		Completion: completion,
 	}
 }
 
@@ -349,7 +654,8 @@ func isEventDone[T any](
 	eventCh <-chan T,
 	logger loggerEventLoop.Interface,
 ) <-chan struct{} {
-	result := make(chan struct{})
+	// Буфер, чтобы горутина не зависла, если сигнал уже никто не ждёт
+	result := make(chan struct{}, 1)
 	go func(eventCh <-chan T) {
 		select {
 		case <-ctx.Done():
@@ -367,59 +673,89 @@ func isEventDone[T any](
 	return result
 }
 
-func (e *eventLoop) runScheduledEvent(ctx context.Context, ev event.Interface) {
-	schedCtx, cancel := context.WithCancel(ctx)
-	intervalComponent, _ := ev.Interval()
-	evntInterval := intervalComponent.GetDuration()
-	e.logger.Infow(
-		"Scheduled ev starting with interval",
-		"ev", ev.GetUUID(),
-		"interval", evntInterval,
-	)
-	ticker := time.NewTicker(evntInterval)
-	intervalComponent.SetRunning(true)
-
-	defer cancel()
-	defer intervalComponent.SetRunning(false)
-	defer ticker.Stop()
-
-	exitChan := isEventDone(schedCtx, intervalComponent.GetQuitChannel(), e.logger)
-	for {
-		select {
-		case <-ticker.C:
-			go func(ev event.Interface) {
-				ev.RunFunction(schedCtx)
-				if once, onceErr := ev.Once(); onceErr == nil {
-					once.Do(
-						func() {
-							cancel()
-						},
-					)
-					return
-				}
-			}(ev)
-
-		case <-exitChan:
-			return
+// RemoveEventByUUIDs удаляет события. Перед удалением выполняются системные события BEFORE_REMOVE - если они
+// отменили удаление, возвращаются все UUID из запроса, после - AFTER_REMOVE
+func (e *eventLoop) RemoveEventByUUIDs(uUIDs ...string) []string {
+	events, _ := e.eventsByUUIDs(uUIDs...)
+	lc := removeLifecycle(events)
+	if len(lc.Events) > 0 {
+		if vetoErr := e.lifecycle(context.Background(), lc); vetoErr != nil {
+			return uUIDs
 		}
 	}
+	notRemoved := e.removeEvents(uUIDs...)
+	if len(lc.Events) > 0 {
+		lc.Stage = AFTER_REMOVE
+		_ = e.lifecycle(context.Background(), lc)
+	}
+	return notRemoved
 }
 
-func (e *eventLoop) RemoveEventByUUIDs(uUIDs ...string) []string {
+// removeEvents удаляет события без событий жизненного цикла: так менеджер убирает отработавшие одноразовые события
+// и интервалы, исчерпавшие MaxRuns. Её вызывают и горутины расписаний, поэтому контейнер меняется только под e.mx
+func (e *eventLoop) removeEvents(uUIDs ...string) []string {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	e.stopCronEvents(uUIDs...)
 	return e.events.RemoveEventByUUIDs(uUIDs...)
 }
 
+// RemoveTriggers удаляет триггеры вместе с их событиями. BEFORE_REMOVE и AFTER_REMOVE выполняются для каждого
+// триггера, триггер, удаление которого отменили, возвращается вместе с ненайденными. Зарезервированные имена с
+// префиксом event.NamespacePrefix не удаляются и тоже возвращаются
 func (e *eventLoop) RemoveTriggers(triggers ...string) []string {
-	return e.events.RemoveTriggers(triggers...)
+	result := []string{}
+	for _, trigger := range triggers {
+		if err := event.ValidateTriggerName(trigger); err != nil {
+			e.logger.Warnw("can't remove trigger", "trigger", trigger, "error", err)
+			result = append(result, trigger)
+			continue
+		}
+		result = append(result, e.removeTriggers(trigger)...)
+	}
+	return result
+}
+
+// removeTriggers удаляет триггеры по ключам контейнера, в том числе триггеры пространств имён
+func (e *eventLoop) removeTriggers(triggers ...string) (result []string) {
+	for _, trigger := range triggers {
+		e.mx.RLock()
+		events := e.events.EventsByTrigger(trigger)
+		e.mx.RUnlock()
+
+		lc := removeLifecycle(events)
+		if len(lc.Events) > 0 {
+			lc.Namespace, lc.TriggerName = eventsContainer.SplitTriggerKey(trigger)
+			if vetoErr := e.lifecycle(context.Background(), lc); vetoErr != nil {
+				result = append(result, trigger)
+				continue
+			}
+		}
+		e.mx.Lock()
+		result = append(result, e.events.RemoveTriggers(trigger)...)
+		e.mx.Unlock()
+		if len(lc.Events) > 0 {
+			lc.Stage = AFTER_REMOVE
+			_ = e.lifecycle(context.Background(), lc)
+		}
+	}
+	if result == nil {
+		result = []string{}
+	}
+	return result
 }
 
 // GetAttachedEvents возвращает все события, прикреплённые к triggerName
 func (e *eventLoop) GetAttachedEvents(triggerName string) (result []event.Interface) {
+	e.mx.RLock()
+	defer e.mx.RUnlock()
 	return e.events.EventsByTrigger(triggerName)
 }
 
 func (e *eventLoop) GetEventsByType(eventType string) (result []string, errReturn error) {
+	e.mx.RLock()
 	events := e.events.GetEventsByType(eventType)
+	e.mx.RUnlock()
 
 	result = make([]string, 0, len(events))
 	for _, v := range events {
@@ -429,10 +765,25 @@ func (e *eventLoop) GetEventsByType(eventType string) (result []string, errRetur
 }
 
 func (e *eventLoop) GetTriggerNames() AllTriggers {
-	ReturnIriggers.userTriggers = e.events.GetTriggers()
+	ReturnIriggers.userTriggers = e.triggerNames("")
 	return ReturnIriggers
 }
 
+// triggerNames возвращает имена триггеров пространства имён, "" - общего
+func (e *eventLoop) triggerNames(namespace string) []string {
+	e.mx.RLock()
+	keys := e.events.GetTriggers()
+	e.mx.RUnlock()
+
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if keyNamespace, triggerName := eventsContainer.SplitTriggerKey(key); keyNamespace == namespace {
+			result = append(result, triggerName)
+		}
+	}
+	return result
+}
+
 func (e *eventLoop) checkContext(ctx context.Context, message string, loggerArgs ...string) error {
 	if isContextDone(ctx) {
 		errStr := fmt.Sprintf("%v (%v)", message, ctx.Err())
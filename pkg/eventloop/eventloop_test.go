@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
-	"gitlab.com/YSX/eventloop/pkg/eventloop/internal"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/sync/errgroup"
 )
@@ -26,11 +27,23 @@ type test struct {
 	want int
 }
 
-func ctxWithValueAndTimeout(ctx context.Context, key any, val any, timeout time.Duration) (
-	context.Context,
-	context.CancelFunc,
-) {
-	return context.WithTimeout(context.WithValue(ctx, key, val), timeout)
+// sendResult неблокирующе пишет результат выполнения функции события в канал теста
+func sendResult(ch chan<- string, result string) string {
+	select {
+	case ch <- result:
+	default:
+	}
+	return result
+}
+
+// maxValue возвращает наибольшее числовое значение из результатов триггера
+func maxValue(result TriggerResult) (max int) {
+	for _, v := range result.Values() {
+		if number, err := strconv.Atoi(v); err == nil && number > max {
+			max = number
+		}
+	}
+	return max
 }
 
 func TestToggleOn(t *testing.T) {
@@ -46,9 +59,7 @@ func TestToggleOn(t *testing.T) {
 			number++
 			return fmt.Sprint(number)
 		}
-		execCh      = make(chan string)
-		ctx, cancel = ctxWithValueAndTimeout(context.Background(), internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-		errG        = new(errgroup.Group)
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
 	)
 
 	var (
@@ -63,45 +74,31 @@ func TestToggleOn(t *testing.T) {
 		t.Error("Error creating events: ", errNE1, errNE2)
 	}
 
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, eventDefault)
-		},
-	)
-	<-execCh
+	if err := evLoop.RegisterEvent(ctx, eventDefault); err != nil {
+		t.Error(err)
+	}
 
 	// Выключаем регистрацию и регистрируем
 	evLoop.ToggleEventLoopFuncs(TOGGLENAME)
 
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, eventDefault2)
-		},
-	)
-	<-execCh
+	if err := evLoop.RegisterEvent(ctx, eventDefault2); err == nil {
+		t.Error("Register is disabled, but event registered")
+	}
 
 	// Включаем регистрацию и регаем
 	evLoop.ToggleEventLoopFuncs(TOGGLENAME)
 
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, eventDefault2)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, TRIGGERNAME)
-		},
-	)
-	<-execCh
-	result, _ := strconv.Atoi(<-execCh)
+	if err := evLoop.RegisterEvent(ctx, eventDefault2); err != nil {
+		t.Error(err)
+	}
 
-	if err := errG.Wait(); err != nil {
+	triggerResult, err := evLoop.Trigger(ctx, TRIGGERNAME)
+	if err != nil {
 		t.Log(err)
 	}
-	if number != WANT || result != WANT {
-		t.Errorf("Number: %v; Want: %v", number, WANT)
+
+	if result := maxValue(triggerResult); number != WANT || result != WANT {
+		t.Errorf("Number: %v; Result: %v; Want: %v", number, result, WANT)
 	}
 }
 
@@ -118,9 +115,7 @@ func TestToggleTrigger(t *testing.T) {
 			return strconv.Itoa(number)
 		}
 
-		execCh      = make(chan string)
-		ctx, cancel = ctxWithValueAndTimeout(context.Background(), internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-		errG        = new(errgroup.Group)
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
 	)
 
 	eventDefault, neErr := event.NewEvent(event.Args{Fun: numInc, TriggerName: EVENTNAME})
@@ -130,36 +125,24 @@ func TestToggleTrigger(t *testing.T) {
 
 	defer cancel()
 
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, eventDefault)
-		},
-	)
-	<-execCh
+	if err := evLoop.RegisterEvent(ctx, eventDefault); err != nil {
+		t.Error(err)
+	}
 
 	evLoop.ToggleEventLoopFuncs(TRIGGER)
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, EVENTNAME)
-		},
-	)
-	<-execCh
+	if _, err := evLoop.Trigger(ctx, EVENTNAME); err == nil {
+		t.Error("Trigger is disabled, but event triggered")
+	}
 
 	evLoop.ToggleEventLoopFuncs(TRIGGER)
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, EVENTNAME)
-		},
-	)
-	result, _ := strconv.Atoi(<-execCh)
-
-	if err := errG.Wait(); err != nil {
+	triggerResult, err := evLoop.Trigger(ctx, EVENTNAME)
+	if err != nil {
 		t.Log(err)
 	}
 
-	if result != WANT {
+	if result := maxValue(triggerResult); result != WANT {
 		t.Errorf("Number: %v; Want: %v", result, WANT)
 	}
 }
@@ -252,45 +235,37 @@ func TestStartScheduler(t *testing.T) {
 	)
 	var (
 		number int
+		execCh = make(chan string, EXECUTIONS*2)
 		numInc = func(ctx context.Context) string {
 			number++
-			return strconv.Itoa(number)
+			return sendResult(execCh, strconv.Itoa(number))
 		}
-		execCh      = make(chan string)
-		ctx, cancel = ctxWithValueAndTimeout(context.Background(), internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-		errG        = new(errgroup.Group)
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
 	)
 
 	defer cancel()
 
-	errG.SetLimit(-1)
-
 	evSched, neErr := event.NewEvent(event.Args{Fun: numInc, IntervalTime: INTERVAL, TriggerName: string(INTERVALED)})
 	defer evLoop.RemoveEventByUUIDs(evSched.GetUUID())
 	if neErr != nil {
 		t.Error(neErr)
 	}
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, evSched)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, string(INTERVALED))
-		},
-	)
+	if err := evLoop.RegisterEvent(ctx, evSched); err != nil {
+		t.Error(err)
+	}
+	triggerResult, err := evLoop.Trigger(ctx, string(INTERVALED))
+	if err != nil {
+		t.Log(err)
+	}
+	if len(triggerResult.Events) != 1 || triggerResult.Events[0].Status != event.StatusStarted {
+		t.Errorf("Trigger result = %v; WANT started interval", triggerResult.Events)
+	}
 
 	var result string
 	for i := 0; i < EXECUTIONS; i++ {
 		result = <-execCh
 	}
 
-	if err := errG.Wait(); err != nil {
-		t.Log(err)
-	}
-
 	if intRes, _ := strconv.Atoi(result); intRes != WANT {
 		t.Errorf("Number = %d; WANT %d", intRes, WANT)
 	}
@@ -304,13 +279,12 @@ func TestScheduleEventAfterStartAndStop(t *testing.T) {
 	)
 	var (
 		number int
+		execCh = make(chan string, EXECUTIONS*2)
 		numInc = func(ctx context.Context) string {
 			number++
-			return strconv.Itoa(number)
+			return sendResult(execCh, strconv.Itoa(number))
 		}
-		execCh      = make(chan string)
-		ctx, cancel = ctxWithValueAndTimeout(context.Background(), internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-		errG        = new(errgroup.Group)
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
 	)
 	defer cancel()
 
@@ -319,32 +293,20 @@ func TestScheduleEventAfterStartAndStop(t *testing.T) {
 	if neErr != nil {
 		t.Error(neErr)
 	}
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, string(INTERVALED))
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, evSched)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, string(INTERVALED))
-		},
-	)
+	if _, err := evLoop.Trigger(ctx, string(INTERVALED)); err != nil {
+		t.Log(err)
+	}
+	if err := evLoop.RegisterEvent(ctx, evSched); err != nil {
+		t.Error(err)
+	}
+	if _, err := evLoop.Trigger(ctx, string(INTERVALED)); err != nil {
+		t.Log(err)
+	}
 	var result string
 	for i := 0; i < EXECUTIONS; i++ {
 		result = <-execCh
 	}
 
-	if err := errG.Wait(); err != nil {
-		t.Log(err)
-	}
-
 	if intRes, _ := strconv.Atoi(result); intRes != WANT {
 		t.Errorf("Number = %d; WANT %d or %d", number, WANT, WANT+1)
 	} else {
@@ -354,25 +316,28 @@ func TestScheduleEventAfterStartAndStop(t *testing.T) {
 
 func TestRemoveEvent(t *testing.T) {
 	const (
-		WANT          = 6
+		WANT          = 1
 		TriggerName   = "RemoveEventRegularFirst"
 		Interval      = time.Millisecond * 20
 		IntervalExecs = 5
 	)
 	var (
-		number int
-		numInc = func(ctx context.Context) string {
+		number, intervalNumber int
+		execCh                 = make(chan string, IntervalExecs*2)
+		numInc                 = func(ctx context.Context) string {
 			number++
 			return strconv.Itoa(number)
 		}
-		execCh      = make(chan string)
-		ctx, cancel = ctxWithValueAndTimeout(context.Background(), internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-		errG        = new(errgroup.Group)
+		intervalInc = func(ctx context.Context) string {
+			intervalNumber++
+			return sendResult(execCh, strconv.Itoa(intervalNumber))
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
 	)
 	defer cancel()
 	evSched, neErr1 := event.NewEvent(
 		event.Args{
-			Fun:          numInc,
+			Fun:          intervalInc,
 			IntervalTime: Interval,
 			TriggerName:  string(INTERVALED),
 		},
@@ -385,45 +350,33 @@ func TestRemoveEvent(t *testing.T) {
 		t.Error("error creating events: ", neErr1, neErr2, neErr3, neErr4)
 	}
 
-	registerErrGo(ctx, errG, evSched)
-	registerErrGo(ctx, errG, eventDefault)
-	registerErrGo(ctx, errG, eventDefault2)
-	registerErrGo(ctx, errG, eventDefault3)
-
-	for i := 0; i < 4; i++ {
-		<-execCh
+	if err := evLoop.RegisterEvent(ctx, evSched, eventDefault, eventDefault2, eventDefault3); err != nil {
+		t.Error(err)
 	}
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, string(INTERVALED))
-		},
-	)
+	if _, err := evLoop.Trigger(ctx, string(INTERVALED)); err != nil {
+		t.Log(err)
+	}
 	for i := 0; i < IntervalExecs; i++ {
 		<-execCh
 	}
 
 	t.Log(evLoop.RemoveEventByUUIDs(eventDefault3.GetUUID(), evSched.GetUUID(), eventDefault.GetUUID()))
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, TriggerName)
-		},
-	)
-	result, _ := strconv.Atoi(<-execCh)
+	triggerResult, err := evLoop.Trigger(ctx, TriggerName)
+	if err != nil {
+		t.Log(err)
+	}
+	result := maxValue(triggerResult)
 
 	evLoop.RemoveEventByUUIDs(eventDefault2.GetUUID())
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, TriggerName)
-		},
-	)
-	<-execCh
-
-	if err := errG.Wait(); err != nil {
+	if triggerResult, err = evLoop.Trigger(ctx, TriggerName); err != nil {
 		t.Log(err)
 	}
+	if len(triggerResult.Events) != 0 {
+		t.Errorf("Removed events executed: %v", triggerResult.Events)
+	}
 
 	if result != WANT {
 		t.Errorf("Number = %d; WANT %d", result, WANT)
@@ -440,19 +393,15 @@ func TestSubevent(t *testing.T) {
 	var (
 		mx          sync.Mutex
 		number      int
+		execCh      = make(chan string, WANT*2)
 		numIncMutex = func(ctx context.Context) string {
 			mx.Lock()
 			number++
+			result := strconv.Itoa(number)
 			mx.Unlock()
-			return strconv.Itoa(number)
+			return sendResult(execCh, result)
 		}
-		execCh      = make(chan string)
-		ctx, cancel = ctxWithValueAndTimeout(
-			context.Background(),
-			internal.EXEC_CH_CTX_KEY,
-			execCh,
-			time.Second,
-		)
+		ctx, cancel               = context.WithTimeout(context.Background(), time.Second)
 		errG                      = new(errgroup.Group)
 		eventArgs                 = event.Args{Fun: numIncMutex, TriggerName: TRIGGERNAME}
 		argsListener, argsTrigger = eventArgs, eventArgs
@@ -475,34 +424,30 @@ func TestSubevent(t *testing.T) {
 		t.Error(neErr1, neErr2, neErr3, neErr4, neErr5)
 	}
 
-	registerErrGo(ctx, errG, eventDefault)
-	registerErrGo(ctx, errG, eventDefault2)
-	registerErrGo(ctx, errG, eventDefault3)
+	if err := evLoop.RegisterEvent(ctx, eventDefault, eventDefault2, eventDefault3); err != nil {
+		t.Error(err)
+	}
 
-	for i := 0; i < 3; i++ {
-		<-execCh
+	if err := evLoop.Subscribe(
+		ctx, []event.Interface{eventDefault, eventDefault2, eventDefault3},
+		[]event.Interface{evListener, evListener2},
+	); err != nil {
+		t.Error(err)
 	}
 	errG.Go(
 		func() error {
-			return evLoop.Subscribe(
-				ctx, []event.Interface{eventDefault, eventDefault2, eventDefault3},
-				[]event.Interface{evListener, evListener2},
-			)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, TRIGGERNAME)
+			_, err := evLoop.Trigger(ctx, TRIGGERNAME)
+			return err
 		},
 	)
 	errG.Go(
 		func() error {
-			return evLoop.Trigger(ctx, TRIGGERNAME)
+			_, err := evLoop.Trigger(ctx, TRIGGERNAME)
+			return err
 		},
 	)
 	var result string
-	for i := 0; i < 10; i++ {
+	for i := 0; i < WANT; i++ {
 		result = <-execCh
 	}
 	if err := errG.Wait(); err != nil {
@@ -520,24 +465,29 @@ func TestPrioritySync(t *testing.T) {
 		TRIGGERNAME = "TestPriorSync"
 	)
 	var (
+		mx               sync.Mutex
 		execs            int
 		r                = 'A'
 		defaultEventFunc = func(ctx context.Context) string {
+			mx.Lock()
+			defer mx.Unlock()
 			execs++
 			r++
 			return string(r)
 		}
 		priorityFunc = func(ctx context.Context) string {
+			mx.Lock()
+			defer mx.Unlock()
 			execs++
 			return "DP"
 		}
 		highPriorityFunc = func(ctx context.Context) string {
+			mx.Lock()
+			defer mx.Unlock()
 			execs++
 			return "HP"
 		}
-		resultCh         = make(chan string)
-		ctx, cancel      = ctxWithValueAndTimeout(context.Background(), internal.EXEC_CH_CTX_KEY, resultCh, time.Second)
-		errG             = new(errgroup.Group)
+		ctx, cancel      = context.WithTimeout(context.Background(), time.Second)
 		defaultEventArgs = event.Args{Fun: defaultEventFunc, TriggerName: TRIGGERNAME}
 	)
 
@@ -554,43 +504,35 @@ func TestPrioritySync(t *testing.T) {
 		t.Error(neErr1, neErr2, neErr3, neErr4)
 	}
 
-	registerErrGo(ctx, errG, evNormal1)
-	registerErrGo(ctx, errG, evNormal2)
-	registerErrGo(ctx, errG, evPrior)
-	registerErrGo(ctx, errG, evHighPrior)
-
-	for i := 0; i < 4; i++ {
-		<-resultCh
-	}
-
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(ctx, TRIGGERNAME)
-		},
-	)
-
-	for i := 0; i < 4; i++ {
-		<-resultCh
+	if err := evLoop.RegisterEvent(ctx, evNormal1, evNormal2, evPrior, evHighPrior); err != nil {
+		t.Error(err)
 	}
 
-	if err := errG.Wait(); err != nil {
+	triggerResult, err := evLoop.Trigger(ctx, TRIGGERNAME)
+	if err != nil {
 		t.Log(err)
 	}
 
-	if execs != WANT {
-		t.Errorf("Number = %v; WANT %v", execs, WANT)
+	if execs != WANT || len(triggerResult.Events) != WANT {
+		t.Errorf("Number = %v; Results = %v; WANT %v", execs, len(triggerResult.Events), WANT)
+	}
+	if values := triggerResult.Values(); len(values) == WANT && (values[0] != "HP" || values[1] != "DP") {
+		t.Errorf("Results are not sorted by priority: %v", values)
 	}
 }
 
 // Before-After
 func TestBeforeAfter(t *testing.T) {
 	const (
-		WANT      = 4
+		WANT      = 6
 		EVENTNAME = "BEFORE_AFTER_EVENT"
 	)
 	var (
+		mx               sync.Mutex
 		result           = 0
 		defaultEventFunc = func(ctx context.Context) string {
+			mx.Lock()
+			defer mx.Unlock()
 			result++
 			return fmt.Sprintf("%v", result)
 		}
@@ -638,13 +580,15 @@ func TestBeforeAfter(t *testing.T) {
 
 	errG.Go(
 		func() error {
-			return evLoop.Trigger(ctx, EVENTNAME)
+			_, err := evLoop.Trigger(ctx, EVENTNAME)
+			return err
 		},
 	)
 
 	errG.Go(
 		func() error {
-			return evLoop.Trigger(ctx, "Random Event")
+			_, err := evLoop.Trigger(ctx, "Random Event")
+			return err
 		},
 	)
 
@@ -658,7 +602,97 @@ func TestBeforeAfter(t *testing.T) {
 }
 
 func TestMain(m *testing.M) {
-	evLoop = NewEventLoop(zapcore.DebugLevel.String())
+	evLoop = NewEventLoopWithLevel(zapcore.DebugLevel.String())
 	exitCode := m.Run()
 	os.Exit(exitCode)
 }
+
+// Ожидание AFTER событий одного триггера: память и время на одно ждущее событие. Ожидание - срок в куче
+// планировщика, горутин на события нет
+func BenchmarkEventLoop_After(b *testing.B) {
+	const events = 10_000
+	var (
+		ctx     = context.Background()
+		newLoop = func(b *testing.B, n int) Interface {
+			e := NewEventLoop()
+			for i := 0; i < n; i++ {
+				ev, err := event.NewEvent(
+					event.Args{
+						TriggerName: "Wait",
+						DateAfter:   after.Args{Date: time.Time{}.AddDate(1, 1, 1).Add(time.Hour), IsRelative: true},
+						Fun: func(ctx context.Context) string {
+							return ""
+						},
+					},
+				)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err = e.RegisterEvent(ctx, ev); err != nil {
+					b.Fatal(err)
+				}
+			}
+			return e
+		}
+		// wait вызывает триггер и ждёт, пока все n событий встанут в очередь планировщика
+		wait = func(e Interface, n int) <-chan struct{} {
+			triggered := make(chan struct{})
+			go func() {
+				defer close(triggered)
+				_, _ = e.Trigger(ctx, "Wait")
+			}()
+			waitTimers(e, n)
+			return triggered
+		}
+	)
+	b.Run(
+		"Memory", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var (
+					e         = newLoop(b, events)
+					triggered <-chan struct{}
+				)
+				b.ReportMetric(
+					bytesPerEvent(
+						events, func() {
+							triggered = wait(e, events)
+						},
+					), "B/event",
+				)
+				_, _ = e.Shutdown(ctx)
+				<-triggered
+			}
+		},
+	)
+	b.Run(
+		"Schedule", func(b *testing.B) {
+			e := newLoop(b, b.N)
+			b.ReportAllocs()
+			b.ResetTimer()
+			triggered := wait(e, b.N)
+			b.StopTimer()
+			_, _ = e.Shutdown(ctx)
+			<-triggered
+		},
+	)
+}
+
+// waitTimers ждёт, пока в куче планировщика менеджера окажется хотя бы n сроков
+func waitTimers(e Interface, n int) {
+	timers := e.(*eventLoop).scheduler.(interface{ Len() int })
+	for timers.Len() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// bytesPerEvent - сколько памяти (куча и стеки горутин) заняла create в пересчёте на одно событие
+func bytesPerEvent(events int, create func()) float64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	create()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	used := int64(after.HeapAlloc+after.StackInuse) - int64(before.HeapAlloc+before.StackInuse)
+	return float64(used) / float64(events)
+}
@@ -8,8 +8,6 @@ import (
 	"time"
 
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
-	"gitlab.com/YSX/eventloop/pkg/eventloop/internal"
-	"golang.org/x/sync/errgroup"
 )
 
 func TestOnAndTrigger(t *testing.T) {
@@ -60,11 +58,11 @@ func TriggerOn_NoEventsTriggerWithChannel(
 	name string,
 	_ func(ctx context.Context) string,
 ) string {
-	err := evLoop.Trigger(ctx, name)
+	result, err := evLoop.Trigger(ctx, name)
 	if err != nil {
 		t.Error(err)
 	}
-	return "0"
+	return strconv.Itoa(len(result.Events))
 }
 
 func TriggerOn_NoEventsTrigger(
@@ -73,7 +71,7 @@ func TriggerOn_NoEventsTrigger(
 	name string,
 	_ func(ctx context.Context) string,
 ) string {
-	err := evLoop.Trigger(ctx, name)
+	_, err := evLoop.Trigger(ctx, name)
 
 	if err != nil {
 		t.Errorf("Empty trigger failed: %v", err)
@@ -95,32 +93,18 @@ func TriggerOn_Simple(
 				TriggerName: triggerName,
 			},
 		)
-
-		execCh       = make(chan string)
-		errG, errCtx = errgroup.WithContext(ctx)
-		testCtx, _   = ctxWithValueAndTimeout(errCtx, internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-	)
-
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventDefault)
-		},
 	)
-	<-execCh
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-
-	result := <-execCh
+	if err := evLoop.RegisterEvent(ctx, eventDefault); err != nil {
+		t.Log(err)
+	}
 
-	if err := errG.Wait(); err != nil {
+	result, err := evLoop.Trigger(ctx, triggerName)
+	if err != nil {
 		t.Log(err)
 	}
 
-	return result
+	return strconv.Itoa(maxValue(result))
 }
 
 func TriggerOn_Multiple(
@@ -136,58 +120,31 @@ func TriggerOn_Multiple(
 		}
 		eventDefault, _  = event.NewEvent(eventArgs)
 		eventDefault2, _ = event.NewEvent(eventArgs)
-		execCh           = make(chan string)
-		errG, errCtx     = errgroup.WithContext(ctx)
-		testCtx, _       = ctxWithValueAndTimeout(errCtx, internal.EXEC_CH_CTX_KEY, execCh, time.Second)
 	)
 
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventDefault)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventDefault2)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-
-	for i := 0; i < 2; i++ {
-		result = <-execCh
+	if err := evLoop.RegisterEvent(ctx, eventDefault); err != nil {
+		t.Log(err)
 	}
-
-	if err := errG.Wait(); err != nil {
+	if _, err := evLoop.Trigger(ctx, triggerName); err != nil {
+		t.Log(err)
+	}
+	if err := evLoop.RegisterEvent(ctx, eventDefault2); err != nil {
+		t.Log(err)
+	}
+	triggerResult, err := evLoop.Trigger(ctx, triggerName)
+	if err != nil {
 		t.Log(err)
 	}
 
-	return result
+	return strconv.Itoa(maxValue(triggerResult))
 }
 
 func TriggerOn_Once(
 	ctx context.Context,
-	_ *testing.T,
+	t *testing.T,
 	triggerName string,
 	farg func(ctx context.Context) string,
 ) (result string) {
-	var (
-		errG, errCtx = errgroup.WithContext(ctx)
-		execCh       = make(chan string)
-		testCtx, _   = ctxWithValueAndTimeout(errCtx, internal.EXEC_CH_CTX_KEY, execCh, time.Second)
-	)
-
 	eventSingle, _ := event.NewEvent(
 		event.Args{
 			Fun:         farg,
@@ -195,24 +152,21 @@ func TriggerOn_Once(
 			IsOnce:      true,
 		},
 	)
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventSingle)
-		},
-	)
-	<-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-	result = <-execCh
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-	<-execCh
+	if err := evLoop.RegisterEvent(ctx, eventSingle); err != nil {
+		t.Log(err)
+	}
+	triggerResult, err := evLoop.Trigger(ctx, triggerName)
+	if err != nil {
+		t.Log(err)
+	}
+	result = strconv.Itoa(maxValue(triggerResult))
+
+	if triggerResult, err = evLoop.Trigger(ctx, triggerName); err != nil {
+		t.Log(err)
+	}
+	if len(triggerResult.Events) != 0 {
+		t.Errorf("Once event executed twice: %v", triggerResult.Events)
+	}
 	return result
 }
 
@@ -236,69 +190,19 @@ func TriggerOn_MultipleDefaultAndOnce(
 				IsOnce:      true,
 			},
 		)
-		execCh       = make(chan string)
-		errG, errCtx = errgroup.WithContext(ctx)
-		testCtx, _   = ctxWithValueAndTimeout(
-			errCtx, internal.EXEC_CH_CTX_KEY, execCh,
-			time.Second,
-		)
+		triggerResult TriggerResult
+		err           error
 	)
 
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventFirst)
-		},
-	)
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventSecond)
-		},
-	)
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(testCtx, eventOnce)
-		},
-	)
-	for i := 0; i < 3; i++ {
-		<-execCh
+	if err = evLoop.RegisterEvent(ctx, eventFirst, eventSecond, eventOnce); err != nil {
+		t.Log(err)
 	}
 
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
 	for i := 0; i < 3; i++ {
-		<-execCh
-	}
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-	for i := 0; i < 2; i++ {
-		<-execCh
-	}
-	errG.Go(
-		func() error {
-			return evLoop.Trigger(testCtx, triggerName)
-		},
-	)
-	for i := 0; i < 2; i++ {
-		result = <-execCh
-	}
-
-	if err := errG.Wait(); err != nil {
-		t.Log(err)
+		if triggerResult, err = evLoop.Trigger(ctx, triggerName); err != nil {
+			t.Log(err)
+		}
 	}
 
-	return result
-}
-
-func registerErrGo(ctx context.Context, errG *errgroup.Group, event event.Interface) {
-	errG.Go(
-		func() error {
-			return evLoop.RegisterEvent(ctx, event)
-		},
-	)
+	return strconv.Itoa(maxValue(triggerResult))
 }
@@ -2,14 +2,18 @@ package eventloop
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"gitlab.com/YSX/eventloop/internal/loggerImplementation"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/interval"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
 	"gitlab.com/YSX/eventloop/pkg/logger"
@@ -104,6 +108,7 @@ func Test_eventLoop_GetAttachedEvents(t *testing.T) {
 			tt.name, func(t *testing.T) {
 				lgger, _ := loggerImplementation.NewLogger("DEBUG", "test", "")
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
@@ -193,6 +198,7 @@ func Test_eventLoop_RegisterEvent(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
@@ -254,6 +260,7 @@ func Test_eventLoop_RemoveEventByUUIDs(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:  clock.New(),
 					events: tt.fields.events,
 					mx:     tt.fields.mx,
 					logger: tt.fields.logger,
@@ -325,6 +332,7 @@ func Test_eventLoop_Subscribe(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
@@ -361,11 +369,12 @@ func Test_eventLoop_Trigger(t *testing.T) {
 		triggerName string
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		init    func(el Interface, p event.Interface)
-		wantErr bool
+		name       string
+		fields     fields
+		args       args
+		init       func(el Interface, p event.Interface)
+		wantErr    bool
+		wantEvents int
 	}{
 		{
 			name:    "Context cancelled",
@@ -398,13 +407,15 @@ func Test_eventLoop_Trigger(t *testing.T) {
 			init: func(el Interface, p event.Interface) {
 				el.RegisterEvent(context.Background(), p)
 			},
-			wantErr: false,
+			wantErr:    false,
+			wantEvents: 1,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
@@ -413,9 +424,96 @@ func Test_eventLoop_Trigger(t *testing.T) {
 				if tt.init != nil {
 					tt.init(e, ev)
 				}
-				if err := e.Trigger(tt.args.ctx, tt.args.triggerName); (err != nil) != tt.wantErr {
+				got, err := e.Trigger(tt.args.ctx, tt.args.triggerName)
+				if (err != nil) != tt.wantErr {
 					t.Errorf("Trigger() error = %v, wantErr %v", err, tt.wantErr)
 				}
+				if len(got.Events) != tt.wantEvents {
+					t.Errorf("Trigger() events = %v, want %v", len(got.Events), tt.wantEvents)
+				}
+				for _, res := range got.Events {
+					if res.UUID != ev.GetUUID() || res.Value != "OK" || res.Status != event.StatusDone {
+						t.Errorf("Trigger() result = %+v", res)
+					}
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_TriggerWithPayload(t *testing.T) {
+	// call - данные, которые получила функция события триггера triggerName
+	type call struct {
+		triggerName string
+		payload     any
+	}
+	var (
+		lgger    = newTestLogger()
+		calls    = make(chan call, 3)
+		newEvent = func(triggerName string) event.Interface {
+			ev, _ := event.NewEvent(
+				event.Args{
+					TriggerName: triggerName,
+					PayloadFun: func(ctx context.Context, payload any) (string, error) {
+						calls <- call{triggerName: triggerName, payload: payload}
+						return "", nil
+					},
+				},
+			)
+			return ev
+		}
+		ev       = newEvent("Trig")
+		evBefore = newEvent(string(BEFORE_TRIGGER))
+		evAfter  = newEvent(string(AFTER_TRIGGER))
+	)
+	type args struct {
+		triggerName string
+		payload     any
+	}
+	tests := []struct {
+		name string
+		args args
+		// want - вызовы функций событий по порядку: BEFORE_TRIGGER, сам триггер, AFTER_TRIGGER
+		want []call
+	}{
+		{
+			name: "Default",
+			args: args{triggerName: "Trig", payload: map[string]any{"id": "1"}},
+			want: []call{
+				{triggerName: string(BEFORE_TRIGGER), payload: map[string]any{"id": "1"}},
+				{triggerName: "Trig", payload: map[string]any{"id": "1"}},
+				{triggerName: string(AFTER_TRIGGER), payload: map[string]any{"id": "1"}},
+			},
+		},
+		{
+			name: "No payload",
+			args: args{triggerName: "Trig"},
+			want: []call{
+				{triggerName: string(BEFORE_TRIGGER)},
+				{triggerName: "Trig"},
+				{triggerName: string(AFTER_TRIGGER)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := &eventLoop{
+					clock:  clock.New(),
+					events: eventsContainer.New(),
+					mx:     &sync.RWMutex{},
+					logger: lgger,
+				}
+				e.RegisterEvent(context.Background(), ev, evBefore, evAfter)
+				_, err := e.TriggerWithPayload(context.Background(), tt.args.triggerName, tt.args.payload)
+				if err != nil {
+					t.Errorf("TriggerWithPayload() error = %v", err)
+				}
+				for i, want := range tt.want {
+					if got := <-calls; !reflect.DeepEqual(got, want) {
+						t.Errorf("TriggerWithPayload() call %v = %+v, want %+v", i, got, want)
+					}
+				}
 			},
 		)
 	}
@@ -460,6 +558,7 @@ func Test_eventLoop_checkContext(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
@@ -477,7 +576,7 @@ func Test_eventLoop_checkContext(t *testing.T) {
 	}
 }
 
-func Test_eventLoop_runScheduledEvent(t *testing.T) {
+func Test_eventLoop_startIntervalEvent(t *testing.T) {
 	var (
 		lgger, _        = loggerImplementation.NewLogger("Debug", "test", "test")
 		ctxCancelled, _ = context.WithDeadline(context.Background(), time.Time{})
@@ -504,11 +603,13 @@ func Test_eventLoop_runScheduledEvent(t *testing.T) {
 		name   string
 		fields fields
 		args   args
+		want   bool
 	}{
 		{
 			name:   "Tick",
 			fields: fields{events: eventsContainer.New(), mx: &sync.RWMutex{}, logger: lgger},
 			args:   args{ctx: ctx, ev: ev},
+			want:   true,
 		},
 		{
 			name:   "Stop",
@@ -520,184 +621,307 @@ func Test_eventLoop_runScheduledEvent(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
 					logger:   tt.fields.logger,
 				}
-				e.runScheduledEvent(tt.args.ctx, tt.args.ev)
+				if got := e.startIntervalEvent(tt.args.ctx, tt.args.ev); got != tt.want {
+					t.Errorf("startIntervalEvent() = %v, want %v", got, tt.want)
+				}
+				// Одноразовый интервал останавливается после первого запуска
+				intervalComponent, _ := tt.args.ev.Interval()
+				deadline := time.Now().Add(time.Second)
+				for ; intervalComponent.IsRunning(); time.Sleep(time.Millisecond) {
+					if time.Now().After(deadline) {
+						t.Fatal("interval is still running")
+					}
+				}
 			},
 		)
 	}
 }
 
-func Test_eventLoop_runnerListener(t *testing.T) {
-	var (
-		lgger, _ = loggerImplementation.NewLogger("Debug", "test", "test")
-		initFunc = func(ctx context.Context, ev event.Interface) context.Context {
-			b := false
-			sub, _ := ev.Subscriber()
-			sub.AddChannel("ID", make(chan subscriber.SubChInfo), &b)
-			return logger.WithLogger(ctx, lgger)
-		}
-		ctxCancelled, _ = context.WithDeadline(context.Background(), time.Time{})
-		ev1, _          = event.NewEvent(
-			event.Args{
-				Fun: func(ctx context.Context) string {
-					ch := ctx.Value("chan").(chan struct{})
-					go func() {
-						ch <- struct{}{}
-					}()
-					return ""
-				}, Subscriber: subscriber.Listener,
-			},
-		)
-		ev2, _ = event.NewEvent(
-			event.Args{
-				Fun: func(ctx context.Context) string {
-					return ""
-				}, Subscriber: subscriber.Listener,
+func Test_eventLoop_startIntervalEvent_Overlap(t *testing.T) {
+	var lgger, _ = loggerImplementation.NewLogger("Debug", "test", "test")
+	tests := []struct {
+		name    string
+		overlap interval.OverlapPolicy
+		// timeout - event.Args.Timeout, функция его не соблюдает и выполняется дольше
+		timeout     time.Duration
+		wantOverlap bool
+	}{
+		{
+			name:        "Allow",
+			overlap:     interval.OverlapAllow,
+			wantOverlap: true,
+		},
+		{
+			name:    "Skip",
+			overlap: interval.OverlapSkip,
+		},
+		{
+			name:    "Skip, function ignores timeout",
+			overlap: interval.OverlapSkip,
+			timeout: 2 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var active, maxActive int32
+				ev, _ := event.NewEvent(
+					event.Args{
+						Fun: func(ctx context.Context) string {
+							cur := atomic.AddInt32(&active, 1)
+							defer atomic.AddInt32(&active, -1)
+							for {
+								prev := atomic.LoadInt32(&maxActive)
+								if cur <= prev || atomic.CompareAndSwapInt32(&maxActive, prev, cur) {
+									break
+								}
+							}
+							time.Sleep(20 * time.Millisecond)
+							return "OK"
+						},
+						IntervalTime:    time.Millisecond,
+						IntervalOptions: interval.Options{Overlap: tt.overlap},
+						Timeout:         tt.timeout,
+					},
+				)
+				e := &eventLoop{events: eventsContainer.New(), mx: &sync.RWMutex{}, clock: clock.New(), logger: lgger}
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+
+				e.startIntervalEvent(ctx, ev)
+				<-ctx.Done()
+				if gotOverlap := atomic.LoadInt32(&maxActive) > 1; gotOverlap != tt.wantOverlap {
+					t.Errorf("startIntervalEvent() max parallel runs = %v, want overlap %v", maxActive, tt.wantOverlap)
+				}
 			},
 		)
-	)
-	type fields struct {
-		events   eventsContainer.Interface
-		mx       *sync.RWMutex
-		disabled []EventFunction
-		logger   logger.Interface
-	}
-	type args struct {
-		ctx context.Context
-		v   event.Interface
 	}
+}
+
+func Test_eventLoop_startIntervalEvent_FakeClock(t *testing.T) {
 	tests := []struct {
 		name   string
-		fields fields
-		args   args
-		init   func(ctx context.Context, ev event.Interface) context.Context
+		policy event.MisfirePolicy
+		// wantLateBy - опоздания запусков после того, как часы сдвинули на 3 интервала вместо одного
+		wantLateBy []time.Duration
 	}{
 		{
-			name:   "Default",
-			fields: fields{events: eventsContainer.New(), mx: &sync.RWMutex{}, logger: lgger},
-			args:   args{ctx: context.Background(), v: ev1},
-			init: func(ctx context.Context, ev event.Interface) context.Context {
-				ctx = initFunc(ctx, ev)
-				sub, _ := ev.Subscriber()
-				go func() {
-					for _, subCh := range sub.Channels() {
-						subCh.GetInfoCh() <- 1
-					}
-				}()
-				return context.WithValue(ctx, "chan", sub.Exit())
-			},
+			name:       "Run once",
+			policy:     event.MisfireRunOnce,
+			wantLateBy: []time.Duration{2 * time.Minute},
 		},
 		{
-			name:   "Exit",
-			fields: fields{events: eventsContainer.New(), mx: &sync.RWMutex{}, logger: lgger},
-			args:   args{ctx: ctxCancelled, v: ev2},
-			init:   initFunc,
+			name:       "Run all",
+			policy:     event.MisfireRunAll,
+			wantLateBy: []time.Duration{2 * time.Minute, time.Minute, 0},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				e := &eventLoop{
-					events:   tt.fields.events,
-					mx:       tt.fields.mx,
-					disabled: tt.fields.disabled,
-					logger:   tt.fields.logger,
+				var (
+					fake    = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+					results = make(chan event.Result, 10)
+					// Хук ошибок - единственный способ увидеть результат интервального запуска, поэтому функция
+					// события всегда возвращает ошибку
+					e = NewEventLoop(
+						WithLogger(newTestLogger()), WithClock(fake), WithErrorHook(
+							func(ctx context.Context, result event.Result) {
+								results <- result
+							},
+						),
+					)
+					ctx   = context.Background()
+					ev, _ = event.NewEvent(
+						event.Args{
+							TriggerName:  "Interval",
+							IntervalTime: time.Minute,
+							Misfire:      event.Misfire{Policy: tt.policy},
+							ErrFun: func(ctx context.Context) (string, error) {
+								return "", errors.New("run")
+							},
+						},
+					)
+				)
+				defer e.RemoveEventByUUIDs(ev.GetUUID())
+				if err := e.RegisterEvent(ctx, ev); err != nil {
+					t.Fatal(err)
 				}
-				if tt.init != nil {
-					tt.args.ctx = tt.init(tt.args.ctx, tt.args.v)
+				if _, err := e.Trigger(ctx, "Interval"); err != nil {
+					t.Fatal(err)
+				}
+				fake.BlockUntil(1)
+
+				fake.Advance(time.Minute)
+				if got := <-results; got.LateBy != 0 || !got.Start.Equal(fake.Now()) {
+					t.Errorf("run in time: LateBy = %v, Start = %v, want 0, %v", got.LateBy, got.Start, fake.Now())
+				}
+
+				fake.Advance(3 * time.Minute)
+				for _, want := range tt.wantLateBy {
+					if got := <-results; got.LateBy != want {
+						t.Errorf("late run: LateBy = %v, want %v", got.LateBy, want)
+					}
+				}
+				select {
+				case got := <-results:
+					t.Errorf("unexpected run with LateBy = %v", got.LateBy)
+				default:
 				}
-				e.runnerListener(tt.args.ctx, tt.args.v)
 			},
 		)
 	}
 }
 
-func Test_eventLoop_runnerTrigger(t *testing.T) {
+func Test_eventLoop_startIntervalEvent_AlignMaxRuns(t *testing.T) {
 	var (
-		lgger, _ = loggerImplementation.NewLogger("Debug", "test", "test")
-		initFunc = func(ctx context.Context, ev event.Interface) context.Context {
-			b := false
-			sub, _ := ev.Subscriber()
-			sub.AddChannel("ID", make(chan subscriber.SubChInfo), &b)
-			return logger.WithLogger(ctx, lgger)
-		}
-		ev1, _ = event.NewEvent(
+		fake    = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 20, 0, time.UTC))
+		results = make(chan event.Result, 10)
+		e       = NewEventLoop(
+			WithLogger(newTestLogger()), WithClock(fake), WithErrorHook(
+				func(ctx context.Context, result event.Result) {
+					results <- result
+				},
+			),
+		)
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
 			event.Args{
-				Fun: func(ctx context.Context) string {
-					return ""
-				}, Subscriber: subscriber.Trigger,
+				TriggerName:     "Interval",
+				IntervalTime:    time.Minute,
+				IntervalOptions: interval.Options{Align: time.Minute, MaxRuns: 2},
+				ErrFun: func(ctx context.Context) (string, error) {
+					return "", errors.New("run")
+				},
 			},
 		)
-		ev2, _ = event.NewEvent(
+	)
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Trigger(ctx, "Interval"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []time.Time{
+		time.Date(2023, 3, 10, 12, 1, 0, 0, time.UTC),
+		time.Date(2023, 3, 10, 12, 2, 0, 0, time.UTC),
+	} {
+		fake.BlockUntil(1)
+		fake.Set(want)
+		if got := <-results; !got.Start.Equal(want) {
+			t.Errorf("run Start = %v, want %v", got.Start, want)
+		}
+	}
+
+	intervalComponent, _ := ev.Interval()
+	for deadline := time.Now().Add(time.Second); intervalComponent.IsRunning(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("interval is still running after MaxRuns")
+		}
+	}
+	if got := e.GetAttachedEvents("Interval"); len(got) != 0 {
+		t.Errorf("GetAttachedEvents() = %v, want event removed after MaxRuns", got)
+	}
+	fake.Advance(time.Hour)
+	select {
+	case got := <-results:
+		t.Errorf("unexpected run after MaxRuns at %v", got.Start)
+	default:
+	}
+}
+
+func Test_eventLoop_triggerEventFunc_FakeClock(t *testing.T) {
+	var (
+		fake  = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+		e     = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
 			event.Args{
+				TriggerName: "Trig",
+				DateAfter:   after.Args{Date: time.Time{}.AddDate(1, 1, 1).Add(time.Hour), IsRelative: true},
 				Fun: func(ctx context.Context) string {
-					return ""
-				}, Subscriber: subscriber.Trigger,
+					return "OK"
+				},
 			},
 		)
-		ctxCancelled, _ = context.WithDeadline(context.Background(), time.Time{})
 	)
-	type fields struct {
-		events   eventsContainer.Interface
-		mx       *sync.RWMutex
-		disabled []EventFunction
-		logger   logger.Interface
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
 	}
-	type args struct {
-		ctx context.Context
-		v   event.Interface
+	scheduled, err := e.Trigger(ctx, "Trig")
+	if err != nil {
+		t.Fatal(err)
 	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		init   func(ctx context.Context, ev event.Interface) context.Context
-	}{
-		{
-			name: "Default",
-			fields: fields{
-				events: eventsContainer.New(), mx: &sync.RWMutex{}, logger: lgger,
-			},
-			args: args{ctx: context.Background(), v: ev1},
-			init: func(ctx context.Context, ev event.Interface) context.Context {
-				ctx = initFunc(ctx, ev)
-				sub, _ := ev.Subscriber()
-				go func() {
-					sub.ChanTrigger() <- struct{}{}
-					for _, subCh := range sub.Channels() {
-						<-subCh.GetInfoCh()
-					}
-					sub.Exit() <- struct{}{}
-				}()
-				return context.WithValue(ctx, "chan", sub.Exit())
-			},
-		},
-		{
-			name:   "Exit",
-			fields: fields{events: eventsContainer.New(), mx: &sync.RWMutex{}, logger: lgger},
-			args:   args{ctx: ctxCancelled, v: ev2},
-			init:   initFunc,
-		},
+	if len(scheduled.Events) != 1 || scheduled.Events[0].Status != event.StatusScheduled {
+		t.Fatalf("Trigger() = %+v, want scheduled", scheduled.Events)
 	}
-	for _, tt := range tests {
-		t.Run(
-			tt.name, func(t *testing.T) {
-				e := &eventLoop{
-					events:   tt.fields.events,
-					mx:       tt.fields.mx,
-					disabled: tt.fields.disabled,
-					logger:   tt.fields.logger,
-				}
-				if tt.init != nil {
-					tt.args.ctx = tt.init(tt.args.ctx, tt.args.v)
-				}
-				e.runnerTrigger(tt.args.ctx, tt.args.v)
+
+	fake.BlockUntil(1)
+	fake.Advance(59 * time.Minute)
+	select {
+	case <-scheduled.Events[0].Completion:
+		t.Fatal("AFTER event executed before time")
+	default:
+	}
+
+	fake.Advance(time.Minute)
+	got := <-scheduled.Events[0].Completion
+	if got.Status != event.StatusDone || !got.Start.Equal(fake.Now()) {
+		t.Errorf("Trigger() completion = %+v, want done at %v", got, fake.Now())
+	}
+}
+
+func Test_eventLoop_Trigger_AfterShortDeadline(t *testing.T) {
+	var (
+		fake        = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+		e           = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+		// runErr - ошибка контекста, с которым выполнилась функция события
+		runErr = make(chan error, 1)
+		ev, _  = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig",
+				DateAfter:   after.Args{Date: time.Time{}.AddDate(1, 1, 1).Add(3 * time.Second), IsRelative: true},
+				Fun: func(ctx context.Context) string {
+					runErr <- ctx.Err()
+					return "OK"
+				},
 			},
 		)
+	)
+	defer cancel()
+	if err := e.RegisterEvent(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	got, err := e.Trigger(ctx, "Trig")
+	if err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Trigger() blocked for %v on AFTER event", elapsed)
+	}
+	if len(got.Events) != 1 || got.Events[0].Status != event.StatusScheduled {
+		t.Fatalf("Trigger() = %+v, want scheduled", got.Events)
+	}
+
+	// Вызов триггера закончился раньше времени события - событие всё равно выполняется
+	<-ctx.Done()
+	fake.BlockUntil(1)
+	fake.Advance(3 * time.Second)
+	if completion := <-got.Events[0].Completion; completion.Status != event.StatusDone {
+		t.Errorf("Trigger() completion = %+v, want %v", completion, event.StatusDone)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("event function context error = %v, want nil", err)
 	}
 }
 
@@ -771,71 +995,24 @@ func Test_eventLoop_triggerEventFunc(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
 					logger:   tt.fields.logger,
 				}
-				tt.args.ctx = logger.WithLogger(tt.args.ctx, tt.fields.logger)
+				ctx, ev := logger.WithLogger(tt.args.ctx, tt.fields.logger), tt.args.ev
+				wg := sync.WaitGroup{}
 				if tt.args.isRunTwice {
+					wg.Add(1)
 					go func() {
+						defer wg.Done()
 						time.Sleep(time.Millisecond)
-						e.triggerEventFunc(tt.args.ctx, tt.args.ev)
+						e.triggerEventFunc(ctx, ev, 0)
 					}()
 				}
-				e.triggerEventFunc(tt.args.ctx, tt.args.ev)
-			},
-		)
-	}
-}
-
-func Test_eventLoop_triggerEventFuncList(t *testing.T) {
-	var (
-		lgger, _ = loggerImplementation.NewLogger("Debug", "test", "test")
-		ev, _    = event.NewEvent(
-			event.Args{
-				TriggerName: "TRIG", Fun: func(ctx context.Context) string {
-					return ""
-				},
-			},
-		)
-	)
-	type fields struct {
-		events   eventsContainer.Interface
-		mx       *sync.RWMutex
-		disabled []EventFunction
-		logger   logger.Interface
-	}
-	type args struct {
-		ctx  context.Context
-		list []event.Interface
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-	}{
-		{
-			name: "Default",
-			fields: fields{
-				events: eventsContainer.New(),
-				mx:     &sync.RWMutex{},
-				logger: lgger,
-			},
-			args: args{ctx: context.Background(), list: []event.Interface{ev, ev}},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(
-			tt.name, func(t *testing.T) {
-				e := &eventLoop{
-					events:   tt.fields.events,
-					mx:       tt.fields.mx,
-					disabled: tt.fields.disabled,
-					logger:   tt.fields.logger,
-				}
-				tt.args.ctx = logger.WithLogger(tt.args.ctx, tt.fields.logger)
-				e.triggerEventFuncList(tt.args.ctx, tt.args.list...)
+				e.triggerEventFunc(ctx, ev, 0)
+				wg.Wait()
 			},
 		)
 	}
@@ -997,6 +1174,7 @@ func Test_eventLoop_GetTriggerNames(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				e := &eventLoop{
+					clock:    clock.New(),
 					events:   tt.fields.events,
 					mx:       tt.fields.mx,
 					disabled: tt.fields.disabled,
@@ -1014,3 +1192,64 @@ func Test_eventLoop_GetTriggerNames(t *testing.T) {
 		)
 	}
 }
+
+func Test_eventLoop_Trigger_Patterns(t *testing.T) {
+	var (
+		e   = NewEventLoop(WithLogger(newTestLogger()))
+		ctx = context.Background()
+	)
+	for _, triggerName := range []string{"order.created", "order.*", "order.#", "user.*"} {
+		name := triggerName
+		ev, _ := event.NewEvent(
+			event.Args{
+				TriggerName: name,
+				Fun: func(ctx context.Context) string {
+					return name
+				},
+			},
+		)
+		if err := e.RegisterEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		triggerName string
+		want        []string
+	}{
+		{
+			name:        "Exact and patterns",
+			triggerName: "order.created",
+			want:        []string{"order.#", "order.*", "order.created"},
+		},
+		{
+			name:        "Hash only",
+			triggerName: "order.created.eu",
+			want:        []string{"order.#"},
+		},
+		{
+			name:        "Other namespace",
+			triggerName: "user.deleted",
+			want:        []string{"user.*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				result, err := e.Trigger(ctx, tt.triggerName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := make([]string, 0, len(result.Events))
+				for _, r := range result.Events {
+					got = append(got, r.Value)
+				}
+				slices.Sort(got)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Trigger() ran %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,241 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/workerPool"
+)
+
+// workerKey - ключ контекста, которым goRun помечает выполнение внутри воркера пула
+type workerKey struct{}
+
+// execute - одно выполнение функции события. Если настроен пул воркеров, функция выполняется в нём, а execute ждёт
+// результат. Событие, которое пул не принял или выкинул из очереди, не выполняется и получает StatusDropped или
+// StatusRejected. После Shutdown события не выполняются и получают StatusCancelled
+func (e *eventLoop) execute(ctx context.Context, ev event.Interface) event.Result {
+	if !e.executions.begin(ev) {
+		return e.notExecutedResult(ev, ErrShutdown)
+	}
+	defer e.executions.end(ev)
+
+	// Внутри воркера (см. goRun) место в пуле уже занято
+	if e.pool == nil || ctx.Value(workerKey{}) != nil {
+		return ev.RunFunction(ctx)
+	}
+
+	done := make(chan event.Result, 1)
+	err := e.pool.Submit(
+		ctx, workerPool.Task{
+			Run: func() {
+				result := ev.RunFunction(ctx)
+				done <- result
+				// Воркер занят, пока брошенная по таймауту функция не вернётся
+				waitTimedOut(result)
+			},
+			Drop: func(err error) {
+				done <- e.notExecutedResult(ev, err)
+			},
+		},
+	)
+	if err != nil {
+		return e.notExecutedResult(ev, err)
+	}
+	return <-done
+}
+
+// goRun выполняет run в фоне. Без пула воркеров - в своей горутине. С пулом run ставится в очередь пула прямо из
+// вызывающей горутины и выполняется воркером целиком, вместе с middleware и повторами. Так запуски, ждущие места в
+// пуле, не держат по горутине: при полной очереди ждёт сам вызывающий (OverflowBlock), либо запуск не выполняется -
+// тогда вместо run вызывается skip с ошибкой пула. Воркер освобождается, когда вернулись и брошенные по таймауту функции
+func (e *eventLoop) goRun(ctx context.Context, run func(ctx context.Context) event.Result, skip func(err error)) {
+	if e.pool == nil {
+		go run(ctx)
+		return
+	}
+	workerCtx := context.WithValue(ctx, workerKey{}, true)
+	err := e.pool.Submit(
+		ctx, workerPool.Task{
+			Run: func() {
+				waitTimedOut(run(workerCtx))
+			},
+			Drop: skip,
+		},
+	)
+	if err != nil {
+		skip(err)
+	}
+}
+
+// spawn - goRun для функций часов (AfterFunc): они выполняются в горутине планировщика, поэтому не должны ждать место
+// в пуле, иначе встанут все таймеры менеджера. Без пула run сразу уходит в свою горутину, с пулом - в очередь, которую
+// до опустошения разбирает одна горутина
+func (e *eventLoop) spawn(ctx context.Context, run func(ctx context.Context) event.Result, skip func(err error)) {
+	if e.pool == nil {
+		go run(ctx)
+		return
+	}
+	if e.spawned.push(
+		func() {
+			e.goRun(ctx, run, skip)
+		},
+	) {
+		go e.spawned.drain()
+	}
+}
+
+// spawnQueue - запуски из функций часов, которые ждут передачи в пул воркеров (см. spawn)
+type spawnQueue struct {
+	mx      sync.Mutex
+	queue   []func()
+	running bool
+}
+
+// push ставит запуск в очередь. true - горутина, которая разбирает очередь, не запущена и её нужно запустить
+func (q *spawnQueue) push(f func()) bool {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	q.queue = append(q.queue, f)
+	if q.running {
+		return false
+	}
+	q.running = true
+	return true
+}
+
+// drain передаёт запуски в пул по очереди, пока она не опустеет
+func (q *spawnQueue) drain() {
+	for {
+		q.mx.Lock()
+		if len(q.queue) == 0 {
+			q.running = false
+			q.mx.Unlock()
+			return
+		}
+		f := q.queue[0]
+		q.queue[0] = nil
+		q.queue = q.queue[1:]
+		q.mx.Unlock()
+		f()
+	}
+}
+
+func (e *eventLoop) notExecutedResult(ev event.Interface, err error) event.Result {
+	now := e.clock.Now()
+	result := event.Result{
+		UUID: ev.GetUUID(), Priority: ev.GetPriority(), Status: event.StatusFailed, Err: err, Start: now, End: now,
+	}
+	switch {
+	case errors.Is(err, workerPool.ErrRejected):
+		result.Status = event.StatusRejected
+	case errors.Is(err, workerPool.ErrDropped), errors.Is(err, workerPool.ErrClosed):
+		result.Status = event.StatusDropped
+	case errors.Is(err, ErrShutdown), errors.Is(err, ErrEventCancelled):
+		result.Status = event.StatusCancelled
+	}
+	e.logger.Warnw("Event function was not executed", "eventId", ev.GetUUID(), "status", result.Status, "error", err)
+	return result
+}
+
+// runEventFunction выполняет функцию события через middleware (см. Use) с учётом его политики повторов. Паника в
+// middleware, как и в функции события, превращается в StatusFailed с *event.PanicError в Result.Err
+func (e *eventLoop) runEventFunction(ctx context.Context, ev event.Interface) (result event.Result) {
+	if ev.IsPaused() {
+		return e.pausedResult(ev)
+	}
+	start := e.clock.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result = event.Result{
+				UUID: ev.GetUUID(), Priority: ev.GetPriority(), Status: event.StatusFailed,
+				Err: &event.PanicError{Value: r, Stack: debug.Stack()}, Start: start, End: e.clock.Now(),
+			}
+			e.logger.Warnw("Event middleware panicked", "eventId", ev.GetUUID(), "error", result.Err)
+		}
+	}()
+	return e.withMiddleware(ev, e.runWithRetry)(ctx, ev)
+}
+
+// runWithRetry выполняет функцию события с учётом его политики повторов. Каждая попытка логируется и попадает в
+// Result.Attempts. Между попытками ждём задержку из политики, либо пока контекст не закончится.
+func (e *eventLoop) runWithRetry(ctx context.Context, ev event.Interface) event.Result {
+	policy, errRetry := ev.Retry()
+	if errRetry != nil {
+		return e.execute(ctx, ev)
+	}
+
+	var attempts []event.Attempt
+	for attempt := 1; ; attempt++ {
+		result := e.execute(ctx, ev)
+		attempts = append(
+			attempts, event.Attempt{Number: attempt, Err: result.Err, Start: result.Start, End: result.End},
+		)
+
+		if result.Err == nil {
+			e.logger.Debugw("Event attempt succeeded", "eventId", ev.GetUUID(), "attempt", attempt)
+			return withAttempts(result, attempts, false)
+		}
+		if attempt >= policy.MaxAttempts() {
+			e.logger.Errorw(
+				"Event retries exhausted",
+				"eventId", ev.GetUUID(),
+				"attempts", attempt,
+				"error", result.Err,
+			)
+			return withAttempts(result, attempts, true)
+		}
+		if !policy.IsRetryable(result.Err) {
+			e.logger.Warnw(
+				"Event attempt failed, error is not retryable",
+				"eventId", ev.GetUUID(),
+				"attempt", attempt,
+				"error", result.Err,
+			)
+			return withAttempts(result, attempts, false)
+		}
+
+		delay := policy.Delay(attempt)
+		e.logger.Warnw(
+			"Event attempt failed, retrying",
+			"eventId", ev.GetUUID(),
+			"attempt", attempt,
+			"maxAttempts", policy.MaxAttempts(),
+			"delay", delay,
+			"error", result.Err,
+		)
+
+		timer := e.timers().NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			e.logger.Warnw("Event retries cancelled, context is done", "eventId", ev.GetUUID(), "attempt", attempt)
+			return withAttempts(result, attempts, false)
+		case <-timer.C():
+		}
+	}
+}
+
+// waitTimedOut ждёт функции, которые выполнение result бросило по таймауту (во всех попытках), но которые ещё
+// работают. Запуск считается законченным, только когда вернулись и они
+func waitTimedOut(result event.Result) {
+	errs := []error{result.Err}
+	for _, attempt := range result.Attempts {
+		errs = append(errs, attempt.Err)
+	}
+	for _, err := range errs {
+		var timeoutErr *event.TimeoutError
+		if errors.As(err, &timeoutErr) && timeoutErr.Done != nil {
+			<-timeoutErr.Done
+		}
+	}
+}
+
+func withAttempts(result event.Result, attempts []event.Attempt, exhausted bool) event.Result {
+	result.Start = attempts[0].Start
+	result.Attempts = attempts
+	result.RetriesExhausted = exhausted
+	return result
+}
@@ -0,0 +1,110 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/retry"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+	"gitlab.com/YSX/eventloop/pkg/logger"
+)
+
+func Test_eventLoop_runEventFunction(t *testing.T) {
+	var (
+		lgger        = newTestLogger()
+		errTemporary = errors.New("temporary")
+		errPermanent = errors.New("permanent")
+		failTimes    = func(times int, err error) event.ErrFunc {
+			var calls int
+			return func(ctx context.Context) (string, error) {
+				calls++
+				if calls <= times {
+					return "", err
+				}
+				return "OK", nil
+			}
+		}
+		retryArgs = retry.Args{
+			MaxAttempts: 3, Backoff: retry.Exponential, Delay: time.Millisecond,
+			Retryable: func(err error) bool {
+				return !errors.Is(err, errPermanent)
+			},
+		}
+	)
+	tests := []struct {
+		name          string
+		args          event.Args
+		wantAttempts  int
+		wantErr       bool
+		wantExhausted bool
+	}{
+		{
+			name:         "No retry",
+			args:         event.Args{TriggerName: "T", ErrFun: failTimes(1, errTemporary)},
+			wantAttempts: 0,
+			wantErr:      true,
+		},
+		{
+			name:         "Success after retry",
+			args:         event.Args{TriggerName: "T", ErrFun: failTimes(2, errTemporary), Retry: retryArgs},
+			wantAttempts: 3,
+		},
+		{
+			name:          "Exhausted",
+			args:          event.Args{TriggerName: "T", ErrFun: failTimes(5, errTemporary), Retry: retryArgs},
+			wantAttempts:  3,
+			wantErr:       true,
+			wantExhausted: true,
+		},
+		{
+			name:         "Not retryable",
+			args:         event.Args{TriggerName: "T", ErrFun: failTimes(5, errPermanent), Retry: retryArgs},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name: "Timeout on each attempt",
+			args: event.Args{
+				TriggerName: "T", Retry: retryArgs, Timeout: 5 * time.Millisecond,
+				Fun: func(ctx context.Context) string {
+					<-ctx.Done()
+					return ""
+				},
+			},
+			wantAttempts:  3,
+			wantErr:       true,
+			wantExhausted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := &eventLoop{
+					clock:  clock.New(),
+					events: eventsContainer.New(),
+					mx:     &sync.RWMutex{},
+					logger: lgger,
+				}
+				ev, err := event.NewEvent(tt.args)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := e.runEventFunction(logger.WithLogger(context.Background(), lgger), ev)
+				if len(got.Attempts) != tt.wantAttempts {
+					t.Errorf("runEventFunction() attempts = %v, want %v", len(got.Attempts), tt.wantAttempts)
+				}
+				if (got.Err != nil) != tt.wantErr || got.RetriesExhausted != tt.wantExhausted {
+					t.Errorf(
+						"runEventFunction() error = %v, exhausted = %v, want error %v, exhausted %v",
+						got.Err, got.RetriesExhausted, tt.wantErr, tt.wantExhausted,
+					)
+				}
+			},
+		)
+	}
+}
@@ -8,14 +8,50 @@ import (
 
 type Interface interface {
 	RegisterEvent(ctx context.Context, newEvent ...event.Interface) error
-	Trigger(ctx context.Context, triggerName string) error
+	// Trigger выполняет события триггера и события включённых триггеров-шаблонов (order.*, order.#), совпадающих с его
+	// именем (см. event.Args.TriggerName)
+	Trigger(ctx context.Context, triggerName string) (TriggerResult, error)
+	// TriggerWithPayload работает как Trigger, но передаёт payload функциям событий триггера (см. event.PayloadFunc)
+	TriggerWithPayload(ctx context.Context, triggerName string, payload any) (TriggerResult, error)
+	// OnError добавляет хуки, которые вызываются для каждого выполнения события, завершившегося ошибкой или паникой
+	OnError(hooks ...ErrorHook)
+	// Use добавляет middleware для выполнения всех событий, UseTrigger - для событий одного триггера. Порядок: общие
+	// middleware снаружи, в порядке добавления, внутри них - middleware триггера
+	Use(middleware ...Middleware)
+	UseTrigger(triggerName string, middleware ...Middleware) error
+	// ConfigureTrigger задаёт режим выполнения событий триггера: Parallel, Sequential или PriorityTiers
+	ConfigureTrigger(triggerName string, config TriggerConfig) error
+	GetTriggerConfig(triggerName string) TriggerConfig
 	ToggleEventLoopFuncs(eventFunc ...EventFunction) string
 	ToggleTriggers(triggerNames ...string) string
 	// RemoveEventByUUIDs удаляет событие по срезу идентификаторов. Возвращает срез оставшихся событий из запроса, которые
 	// не были удалены
 	RemoveEventByUUIDs(UUIDs ...string) []string
 	RemoveTriggers(triggers ...string) []string
+	// PauseEvent ставит события на паузу, ResumeEvent снимает с неё, CancelEvent удаляет события вместе с их
+	// интервалами, cron и ожиданиями AFTER. Все три возвращают UUID из запроса, событий с которыми нет
+	PauseEvent(UUIDs ...string) []string
+	ResumeEvent(UUIDs ...string) []string
+	CancelEvent(UUIDs ...string) []string
+	// StartInterval и StopInterval запускают и останавливают одно интервальное событие, не трогая остальные, в отличие
+	// от триггера INTERVALED, который переключает все интервалы сразу
+	StartInterval(UUID string) error
+	StopInterval(UUID string) error
 	Subscribe(ctx context.Context, triggers []event.Interface, listeners []event.Interface) error
+	// Unsubscribe убирает связи слушателей с триггерами по UUID событий, не трогая остальные связи. Возвращает UUID из
+	// запроса, которые не подписаны
+	Unsubscribe(triggers []string, listeners []string) []string
 	GetAttachedEvents(triggerName string) (result []event.Interface)
 	GetTriggerNames() AllTriggers
+	// ConfigureNamespace задаёт квоты пространства имён, Namespace возвращает менеджер, ограниченный пространством
+	// имён: его триггеры и события не пересекаются с другими пространствами
+	ConfigureNamespace(namespace string, quota Quota) error
+	Namespace(namespace string) (Interface, error)
+	// WorkerPoolStats возвращает состояние пула воркеров (см. WithWorkerPool), в том числе глубину очереди
+	WorkerPoolStats() WorkerPoolStats
+	// Shutdown останавливает менеджер событий и ждёт выполняющиеся функции событий, пока жив ctx
+	Shutdown(ctx context.Context) (ShutdownReport, error)
+	// InitError - ошибка опции, которую не удалось применить при создании менеджера: например, WithLogLevel не смог
+	// создать логгер, и логи никуда не пишутся. nil - все опции применены
+	InitError() error
 }
@@ -16,9 +16,10 @@ type (
 )
 
 const (
-	TRIGGER  criteriaName = "TRIGGER"
-	TYPE     criteriaName = "TYPE"
-	PRIORITY criteriaName = "PRIORITY"
+	TRIGGER   criteriaName = "TRIGGER"
+	TYPE      criteriaName = "TYPE"
+	PRIORITY  criteriaName = "PRIORITY"
+	NAMESPACE criteriaName = "NAMESPACE"
 )
 
 type criteriaInfo struct {
@@ -30,6 +31,8 @@ type eventsList struct {
 	events eventsMap
 
 	eventsByCriteria eventsByCriteriaName
+	// patterns - триггеры-шаблоны (order.*, order.#) из eventsByCriteria[TRIGGER]
+	patterns *patternTrie
 
 	mx sync.Mutex
 }
@@ -38,10 +41,12 @@ func New() Interface {
 	result := eventsList{
 		events: make(eventsMap),
 		eventsByCriteria: eventsByCriteriaName{
-			"TRIGGER":  make(eventsByCriteria),
-			"TYPE":     make(eventsByCriteria),
-			"PRIORITY": make(eventsByCriteria),
+			"TRIGGER":   make(eventsByCriteria),
+			"TYPE":      make(eventsByCriteria),
+			"PRIORITY":  make(eventsByCriteria),
+			"NAMESPACE": make(eventsByCriteria),
 		},
+		patterns: newPatternTrie(),
 	}
 	return &result
 }
@@ -51,8 +56,11 @@ func (el *eventsList) AddEvent(newEvent event.Interface) {
 	for criteria, events := range el.eventsByCriteria {
 		switch criteria {
 		case "TRIGGER":
-			triggerName := newEvent.GetTriggerName()
+			triggerName := TriggerKey(newEvent.GetNamespace(), newEvent.GetTriggerName())
 			events[triggerName] = el.addToMap(events[triggerName], newEvent)
+			if el.patterns != nil && isPattern(triggerName) {
+				el.patterns.insert(triggerName)
+			}
 		case "TYPE":
 			for _, t := range newEvent.GetTypes() {
 				events[string(t)] = el.addToMap(
@@ -63,6 +71,9 @@ func (el *eventsList) AddEvent(newEvent event.Interface) {
 		case "PRIORITY":
 			priority := newEvent.GetPriorityString()
 			events[priority] = el.addToMap(events[priority], newEvent)
+		case "NAMESPACE":
+			namespace := newEvent.GetNamespace()
+			events[namespace] = el.addToMap(events[namespace], newEvent)
 		default:
 			panic(criteria + " add event not implemented")
 		}
@@ -91,6 +102,11 @@ func (el *eventsList) GetAll() (result []event.Interface) {
 	return maps.Values(el.events)
 }
 
+// GetEventsByNamespace возвращает события пространства имён, "" - общего
+func (el *eventsList) GetEventsByNamespace(namespace string) []event.Interface {
+	return maps.Values(el.eventsByCriteria[NAMESPACE][namespace].data)
+}
+
 func (el *eventsList) GetEventsByType(eventType string) []event.Interface {
 	return maps.Values(el.eventsByCriteria[TYPE][eventType].data)
 }
@@ -108,6 +124,7 @@ func (el *eventsList) RemoveEventByUUIDs(uuids ...string) (result []string) {
 			el.removeByTrigger(ev)
 			el.removeByTypes(ev)
 			el.removeByPriority(ev)
+			el.removeByNamespace(ev)
 
 			// Удаляем из общего хранилища
 			delete(el.events, uuid)
@@ -135,8 +152,10 @@ func (el *eventsList) RemoveTriggers(triggers ...string) (result []string) {
 	return result
 }
 
+// GetPrioritySortedEventsByTrigger возвращает события триггера и события включённых триггеров-шаблонов, совпадающих
+// с его именем, по убыванию приоритета
 func (el *eventsList) GetPrioritySortedEventsByTrigger(triggerName string) []event.Interface {
-	result := maps.Values(el.eventsByCriteria[TRIGGER][triggerName].data)
+	result := maps.Values(el.matchingEvents(triggerName))
 	sort.Slice(
 		result, func(i, j int) bool {
 			return result[i].GetPriority() > result[j].GetPriority()
@@ -145,6 +164,30 @@ func (el *eventsList) GetPrioritySortedEventsByTrigger(triggerName string) []eve
 	return result
 }
 
+// matchingEvents собирает события триггера и совпадающих с ним шаблонов его пространства имён. Событие шаблона,
+// совпадающего с самим собой, попадает один раз
+func (el *eventsList) matchingEvents(triggerName string) eventsMap {
+	patterns := el.patterns.match(triggerName)
+	if len(patterns) == 0 {
+		return el.eventsByCriteria[TRIGGER][triggerName].data
+	}
+	result := maps.Clone(el.eventsByCriteria[TRIGGER][triggerName].data)
+	if result == nil {
+		result = make(eventsMap)
+	}
+	namespace, _ := SplitTriggerKey(triggerName)
+	for _, pattern := range patterns {
+		// Шаблоны общего пространства (#, *.created) не должны совпадать с триггерами арендаторов
+		if patternNamespace, _ := SplitTriggerKey(pattern); patternNamespace != namespace {
+			continue
+		}
+		if triggerInfo := el.eventsByCriteria[TRIGGER][pattern]; triggerInfo.isEnabled {
+			maps.Copy(result, triggerInfo.data)
+		}
+	}
+	return result
+}
+
 func (el *eventsList) ToggleTrigger(triggerName string, enable bool) {
 	triggerInfo, ok := el.eventsByCriteria[TRIGGER][triggerName]
 	if !ok {
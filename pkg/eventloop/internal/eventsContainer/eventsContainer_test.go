@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"golang.org/x/exp/slices"
 )
 
 var (
@@ -30,10 +31,12 @@ func TestNew(t *testing.T) {
 			want: &eventsList{
 				events: make(eventsMap),
 				eventsByCriteria: eventsByCriteriaName{
-					"TRIGGER":  make(eventsByCriteria),
-					"TYPE":     make(eventsByCriteria),
-					"PRIORITY": make(eventsByCriteria),
+					"TRIGGER":   make(eventsByCriteria),
+					"TYPE":      make(eventsByCriteria),
+					"PRIORITY":  make(eventsByCriteria),
+					"NAMESPACE": make(eventsByCriteria),
 				},
+				patterns: newPatternTrie(),
 			},
 		},
 	}
@@ -355,3 +358,146 @@ func Test_eventsList_AddEvent(t *testing.T) {
 		)
 	}
 }
+
+func Test_eventsList_GetPrioritySortedEventsByTrigger_Patterns(t *testing.T) {
+	newEvent := func(triggerName string, priority int) event.Interface {
+		ev, _ := event.NewEvent(
+			event.Args{
+				Fun: func(ctx context.Context) string {
+					return ""
+				}, TriggerName: triggerName, Priority: priority,
+			},
+		)
+		return ev
+	}
+	var (
+		exact    = newEvent("order.created", 0)
+		oneSeg   = newEvent("order.*", 2)
+		anySeg   = newEvent("order.#", 1)
+		disabled = newEvent("*.created", 3)
+		other    = newEvent("user.*", 4)
+	)
+	el := New()
+	for _, ev := range []event.Interface{exact, oneSeg, anySeg, disabled, other} {
+		el.AddEvent(ev)
+	}
+	el.ToggleTrigger("*.created", false)
+
+	tests := []struct {
+		name        string
+		triggerName string
+		want        []event.Interface
+	}{
+		{
+			name:        "Exact and patterns",
+			triggerName: "order.created",
+			want:        []event.Interface{oneSeg, anySeg, exact},
+		},
+		{
+			name:        "Only hash matches deeper names",
+			triggerName: "order.created.eu",
+			want:        []event.Interface{anySeg},
+		},
+		{
+			name:        "Pattern name matches itself once",
+			triggerName: "order.*",
+			want:        []event.Interface{oneSeg, anySeg},
+		},
+		{
+			name:        "No match",
+			triggerName: "payment.created",
+			want:        []event.Interface{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := el.GetPrioritySortedEventsByTrigger(tt.triggerName); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("GetPrioritySortedEventsByTrigger() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+
+	if notFound := el.RemoveEventByUUIDs(oneSeg.GetUUID()); len(notFound) != 0 {
+		t.Fatalf("RemoveEventByUUIDs() = %v", notFound)
+	}
+	got := el.GetPrioritySortedEventsByTrigger("order.created")
+	if want := []event.Interface{anySeg, exact}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after removal GetPrioritySortedEventsByTrigger() = %v, want %v", got, want)
+	}
+}
+
+func Test_eventsList_Namespaces(t *testing.T) {
+	newEvent := func(namespace, triggerName string) event.Interface {
+		ev, _ := event.NewEvent(
+			event.Args{
+				Fun: func(ctx context.Context) string {
+					return ""
+				}, TriggerName: triggerName, Namespace: namespace,
+			},
+		)
+		return ev
+	}
+	var (
+		shared    = newEvent("", "order.created")
+		sharedAll = newEvent("", "#")
+		tenant    = newEvent("acme", "order.created")
+		tenantAll = newEvent("acme", "order.#")
+		other     = newEvent("globex", "order.created")
+	)
+	el := New()
+	for _, ev := range []event.Interface{shared, sharedAll, tenant, tenantAll, other} {
+		el.AddEvent(ev)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want []event.Interface
+	}{
+		{
+			name: "Shared",
+			key:  "order.created",
+			want: []event.Interface{shared, sharedAll},
+		},
+		{
+			name: "Tenant does not see shared patterns",
+			key:  TriggerKey("acme", "order.created"),
+			want: []event.Interface{tenant, tenantAll},
+		},
+		{
+			name: "Other tenant",
+			key:  TriggerKey("globex", "order.created"),
+			want: []event.Interface{other},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got := el.GetPrioritySortedEventsByTrigger(tt.key)
+				if !reflect.DeepEqual(uuids(got), uuids(tt.want)) {
+					t.Errorf("GetPrioritySortedEventsByTrigger() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+
+	if got := el.GetEventsByNamespace("acme"); len(got) != 2 {
+		t.Errorf("GetEventsByNamespace() = %v, want 2 events", got)
+	}
+	el.RemoveEventByUUIDs(tenant.GetUUID(), tenantAll.GetUUID())
+	if got := el.GetEventsByNamespace("acme"); len(got) != 0 {
+		t.Errorf("GetEventsByNamespace() after removal = %v, want none", got)
+	}
+}
+
+// uuids - отсортированные UUID событий, чтобы сравнивать наборы событий без учёта порядка
+func uuids(events []event.Interface) []string {
+	result := make([]string, 0, len(events))
+	for _, ev := range events {
+		result = append(result, ev.GetUUID())
+	}
+	slices.Sort(result)
+	return result
+}
@@ -10,6 +10,7 @@ type Interface interface {
 	GetTriggers() []string
 	GetAll() []event.Interface
 	GetEventsByType(eventType string) []event.Interface
+	GetEventsByNamespace(namespace string) []event.Interface
 	RemoveEventByUUIDs(uuids ...string) []string
 	RemoveTriggers(triggers ...string) []string
 	GetPrioritySortedEventsByTrigger(triggerName string) []event.Interface
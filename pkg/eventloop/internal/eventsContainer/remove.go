@@ -8,6 +8,7 @@ func (el *eventsList) removeEvent(e event.Interface) {
 	el.removeByTypes(e)
 	el.removeByPriority(e)
 	el.removeByTrigger(e)
+	el.removeByNamespace(e)
 }
 
 func (el *eventsList) removeTrigger(trig string) {
@@ -18,6 +19,7 @@ func (el *eventsList) removeTrigger(trig string) {
 	// Удаляем триггер после всех удалений
 	if len(triggerInfo.data) == 0 {
 		delete(el.eventsByCriteria[TRIGGER], trig)
+		el.patterns.remove(trig)
 	}
 }
 
@@ -35,7 +37,7 @@ func (el *eventsList) removeByTypes(e event.Interface) {
 }
 
 func (el *eventsList) removeByTrigger(e event.Interface) {
-	triggerName := e.GetTriggerName()
+	triggerName := TriggerKey(e.GetNamespace(), e.GetTriggerName())
 	el._removeHelper(TRIGGER, triggerName, e.GetUUID())
 	// if triggerInfo, ok := el.eventsByCriteria[TRIGGER][triggerName]; ok {
 	// 	delete(triggerInfo.data, e.GetUUID())
@@ -56,12 +58,19 @@ func (el *eventsList) removeByPriority(e event.Interface) {
 	// }
 }
 
+func (el *eventsList) removeByNamespace(e event.Interface) {
+	el._removeHelper(NAMESPACE, e.GetNamespace(), e.GetUUID())
+}
+
 func (el *eventsList) _removeHelper(criteriaName criteriaName, criteria string, uuid string) {
 	if priorityInfo, ok := el.eventsByCriteria[criteriaName][criteria]; ok {
 		delete(priorityInfo.data, uuid)
 
 		if len(priorityInfo.data) == 0 {
 			delete(el.eventsByCriteria[criteriaName], criteria)
+			if criteriaName == TRIGGER {
+				el.patterns.remove(criteria)
+			}
 		}
 	}
 }
@@ -0,0 +1,139 @@
+package eventsContainer
+
+import (
+	"strings"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+const (
+	// patternSeparator разделяет сегменты имени триггера
+	patternSeparator = "."
+	// wildcardOne - сегмент шаблона, совпадающий ровно с одним сегментом имени
+	wildcardOne = "*"
+	// wildcardAny - сегмент шаблона, совпадающий с любым числом сегментов имени, в том числе с нулём
+	wildcardAny = "#"
+	// systemTriggerPrefix - системные триггеры (@INTERVALED, @BEFORE_TRIGGER...) с шаблонами не совпадают
+	systemTriggerPrefix = "@"
+)
+
+// isPattern - есть ли в имени триггера сегменты-шаблоны
+func isPattern(triggerName string) bool {
+	for _, segment := range strings.Split(triggerName, patternSeparator) {
+		if segment == wildcardOne || segment == wildcardAny {
+			return true
+		}
+	}
+	return false
+}
+
+// patternTrie - префиксное дерево шаблонов триггеров по сегментам, как topic exchange в AMQP. Позволяет найти все
+// шаблоны, совпадающие с именем, не перебирая все триггеры
+type patternTrie struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[string]*trieNode
+	// pattern - шаблон, который заканчивается в этом узле. Пустой - не заканчивается
+	pattern string
+}
+
+func newPatternTrie() *patternTrie {
+	return &patternTrie{root: &trieNode{children: make(map[string]*trieNode)}}
+}
+
+func (t *patternTrie) insert(pattern string) {
+	node := t.root
+	for _, segment := range strings.Split(pattern, patternSeparator) {
+		child, ok := node.children[segment]
+		if !ok {
+			child = &trieNode{children: make(map[string]*trieNode)}
+			node.children[segment] = child
+		}
+		node = child
+	}
+	node.pattern = pattern
+}
+
+// remove удаляет шаблон и ставшие пустыми узлы
+func (t *patternTrie) remove(pattern string) {
+	if t == nil {
+		return
+	}
+	var (
+		segments = strings.Split(pattern, patternSeparator)
+		path     = make([]*trieNode, 0, len(segments)+1)
+		node     = t.root
+	)
+	path = append(path, node)
+	for _, segment := range segments {
+		if node = node.children[segment]; node == nil {
+			return
+		}
+		path = append(path, node)
+	}
+	node.pattern = ""
+	for i := len(segments) - 1; i >= 0; i-- {
+		if child := path[i+1]; child.pattern != "" || len(child.children) > 0 {
+			return
+		}
+		delete(path[i].children, segments[i])
+	}
+}
+
+// match возвращает шаблоны, совпадающие с именем триггера
+func (t *patternTrie) match(triggerName string) (result []string) {
+	if t == nil || strings.HasPrefix(triggerName, systemTriggerPrefix) {
+		return nil
+	}
+	found := make(map[string]struct{})
+	t.root.match(strings.Split(triggerName, patternSeparator), found)
+	result = make([]string, 0, len(found))
+	for pattern := range found {
+		result = append(result, pattern)
+	}
+	return result
+}
+
+func (n *trieNode) match(segments []string, found map[string]struct{}) {
+	// # забирает от нуля до всех оставшихся сегментов
+	if child, ok := n.children[wildcardAny]; ok {
+		for i := 0; i <= len(segments); i++ {
+			child.match(segments[i:], found)
+		}
+	}
+	if len(segments) == 0 {
+		if n.pattern != "" {
+			found[n.pattern] = struct{}{}
+		}
+		return
+	}
+	if child, ok := n.children[segments[0]]; ok {
+		child.match(segments[1:], found)
+	}
+	if child, ok := n.children[wildcardOne]; ok {
+		child.match(segments[1:], found)
+	}
+}
+
+// namespacePrefix отличает триггеры пространств имён от триггеров общего пространства
+const namespacePrefix = event.NamespacePrefix
+
+// TriggerKey - имя триггера в контейнере: для пространства имён оно становится первым сегментом, поэтому шаблоны
+// арендатора (order.*) совпадают только с его триггерами. Общее пространство и события без триггера ключ не меняют
+func TriggerKey(namespace, triggerName string) string {
+	if namespace == "" || triggerName == "" {
+		return triggerName
+	}
+	return namespacePrefix + namespace + patternSeparator + triggerName
+}
+
+// SplitTriggerKey разбирает ключ TriggerKey обратно на пространство имён и имя триггера
+func SplitTriggerKey(key string) (namespace, triggerName string) {
+	if !strings.HasPrefix(key, namespacePrefix) {
+		return "", key
+	}
+	namespace, triggerName, _ = strings.Cut(strings.TrimPrefix(key, namespacePrefix), patternSeparator)
+	return namespace, triggerName
+}
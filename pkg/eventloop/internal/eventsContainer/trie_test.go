@@ -0,0 +1,128 @@
+package eventsContainer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_isPattern(t *testing.T) {
+	tests := []struct {
+		triggerName string
+		want        bool
+	}{
+		{triggerName: "order.created"},
+		{triggerName: "order.*", want: true},
+		{triggerName: "#", want: true},
+		{triggerName: "order.created*"},
+		{triggerName: "@INTERVALED"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.triggerName, func(t *testing.T) {
+				if got := isPattern(tt.triggerName); got != tt.want {
+					t.Errorf("isPattern() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_patternTrie_match(t *testing.T) {
+	trie := newPatternTrie()
+	for _, pattern := range []string{"order.*", "order.#", "*.created", "#", "order.*.eu", "order.#.eu"} {
+		trie.insert(pattern)
+	}
+	tests := []struct {
+		name        string
+		triggerName string
+		want        []string
+	}{
+		{
+			name:        "One segment",
+			triggerName: "order.created",
+			want:        []string{"#", "*.created", "order.#", "order.*"},
+		},
+		{
+			name:        "Hash matches zero segments",
+			triggerName: "order",
+			want:        []string{"#", "order.#"},
+		},
+		{
+			name:        "Deep name",
+			triggerName: "order.created.eu",
+			want:        []string{"#", "order.#", "order.#.eu", "order.*.eu"},
+		},
+		{
+			name:        "Hash in the middle",
+			triggerName: "order.a.b.eu",
+			want:        []string{"#", "order.#", "order.#.eu"},
+		},
+		{
+			name:        "System trigger",
+			triggerName: "@BEFORE_TRIGGER",
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got := trie.match(tt.triggerName)
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("match() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_patternTrie_remove(t *testing.T) {
+	trie := newPatternTrie()
+	trie.insert("order.*")
+	trie.insert("order.*.eu")
+
+	trie.remove("order.*")
+	if got := trie.match("order.created"); len(got) != 0 {
+		t.Errorf("match() after remove = %v, want none", got)
+	}
+	if got := trie.match("order.created.eu"); !reflect.DeepEqual(got, []string{"order.*.eu"}) {
+		t.Errorf("match() = %v, want [order.*.eu]", got)
+	}
+
+	trie.remove("order.*.eu")
+	if len(trie.root.children) != 0 {
+		t.Errorf("root children after removing all patterns = %v, want none", trie.root.children)
+	}
+}
+
+func TestTriggerKey(t *testing.T) {
+	tests := []struct {
+		namespace   string
+		triggerName string
+		want        string
+	}{
+		{triggerName: "order.created", want: "order.created"},
+		{namespace: "acme", triggerName: "order.created", want: "$acme.order.created"},
+		{namespace: "acme", triggerName: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.want, func(t *testing.T) {
+				got := TriggerKey(tt.namespace, tt.triggerName)
+				if got != tt.want {
+					t.Fatalf("TriggerKey() = %v, want %v", got, tt.want)
+				}
+				if tt.triggerName == "" {
+					return
+				}
+				if namespace, triggerName := SplitTriggerKey(got); namespace != tt.namespace ||
+					triggerName != tt.triggerName {
+					t.Errorf(
+						"SplitTriggerKey() = %v, %v, want %v, %v", namespace, triggerName, tt.namespace, tt.triggerName,
+					)
+				}
+			},
+		)
+	}
+}
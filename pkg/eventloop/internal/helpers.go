@@ -1,17 +1,10 @@
 package internal
 
 import (
-	"context"
 	"fmt"
 	"sync"
 )
 
-type eventLoopContextKey string
-
-const (
-	EXEC_CH_CTX_KEY eventLoopContextKey = "execCh"
-)
-
 var riMx sync.Mutex
 
 func RemoveSliceItemByIndex[T any](s []T, index int) []T {
@@ -23,12 +16,6 @@ func RemoveSliceItemByIndex[T any](s []T, index int) []T {
 	return s
 }
 
-func WriteToExecCh(ctx context.Context, result string) {
-	if ch := ctx.Value(EXEC_CH_CTX_KEY); ch != nil {
-		ch.(chan string) <- result
-	}
-}
-
 func WrapError(dest error, source error) error {
 	if dest == nil {
 		return source
@@ -1,7 +1,6 @@
 package internal
 
 import (
-	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -63,47 +62,6 @@ func TestRemoveSliceItemByIndex(t *testing.T) {
 	}
 }
 
-func TestWriteToExecCh(t *testing.T) {
-	var ch = make(chan string)
-	type args struct {
-		ctx    context.Context
-		result string
-	}
-	tests := []struct {
-		name string
-		args args
-		init func() string
-		want string
-	}{
-		{
-			name: "Default",
-			args: args{ctx: context.WithValue(context.Background(), EXEC_CH_CTX_KEY, ch),
-				result: "Hello RPTRP"},
-			want: "Hello RPTRP",
-			init: func() string {
-				return <-ch
-			},
-		},
-		{
-			name: "No channel",
-			args: args{ctx: context.Background(), result: "TEST"},
-			want: "",
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			var result string
-			go WriteToExecCh(tt.args.ctx, tt.args.result)
-			if tt.init != nil {
-				result = tt.init()
-			}
-			if result != tt.want {
-				t.Errorf("RemoveSliceItemByIndex() = %v, want %v", result, tt.want)
-			}
-		})
-	}
-}
-
 func TestWrapError(t *testing.T) {
 	type args struct {
 		dest   error
@@ -0,0 +1,237 @@
+package scheduler
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+)
+
+// scheduler - общее ядро всех таймеров и тикеров менеджера событий. Вместо отдельного таймера рантайма на каждое
+// AFTER событие и тикера на каждый интервал все сроки лежат в одной куче: вставка и отмена - O(log n), ближайший срок -
+// O(1). Кучу обслуживает одна горутина с одним таймером базовых часов, и только пока в куче что-то есть. Функции
+// AfterFunc выполняются в этой же горутине, поэтому они должны быстро возвращаться
+type scheduler struct {
+	base clock.Interface
+
+	mx      sync.Mutex
+	queue   queue
+	running bool
+	// wake будит горутину, когда в голову кучи встал срок раньше того, который она ждёт
+	wake chan struct{}
+}
+
+// New возвращает часы, таймеры и тикеры которых обслуживает одна куча. Время и срабатывания берутся из base, поэтому
+// поверх clock.Fake планировщик тоже работает по ручному времени
+func New(base clock.Interface) clock.Interface {
+	return &scheduler{base: base, wake: make(chan struct{}, 1)}
+}
+
+func (s *scheduler) Now() time.Time {
+	return s.base.Now()
+}
+
+func (s *scheduler) Since(t time.Time) time.Duration {
+	return s.base.Since(t)
+}
+
+func (s *scheduler) NewTimer(d time.Duration) clock.Timer {
+	e := &entry{s: s, c: make(chan time.Time, 1), index: -1}
+	e.start(d)
+	return &timer{e: e}
+}
+
+// NewTicker создаёт тикер с периодом d. Как и time.NewTicker, паникует при d <= 0
+func (s *scheduler) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	e := &entry{s: s, c: make(chan time.Time, 1), period: d, index: -1}
+	e.start(d)
+	return &ticker{e: e}
+}
+
+// AfterFunc вызывает f через d в горутине планировщика. Пока f выполняется, остальные сроки не срабатывают
+func (s *scheduler) AfterFunc(d time.Duration, f func()) clock.Timer {
+	e := &entry{s: s, f: f, index: -1}
+	e.start(d)
+	return &timer{e: e}
+}
+
+// Len - сколько таймеров и тикеров сейчас ждут срабатывания
+func (s *scheduler) Len() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return len(s.queue)
+}
+
+// push ставит срок в кучу и при необходимости запускает или будит горутину. Вызывается под s.mx
+func (s *scheduler) push(e *entry) {
+	heap.Push(&s.queue, e)
+	switch {
+	case !s.running:
+		s.running = true
+		go s.run()
+	case e.index == 0:
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run ждёт ближайший срок в куче и срабатывает. Когда куча пустеет - выходит, следующий push запустит её снова
+func (s *scheduler) run() {
+	for {
+		s.mx.Lock()
+		if len(s.queue) == 0 {
+			s.running = false
+			s.mx.Unlock()
+			return
+		}
+		next := s.queue[0].when
+		s.mx.Unlock()
+
+		t := s.base.NewTimer(next.Sub(s.base.Now()))
+		select {
+		case <-t.C():
+		case <-s.wake:
+			t.Stop()
+		}
+		s.fire()
+	}
+}
+
+// fire отправляет время во все таймеры и тикеры, срок которых наступил, и вызывает функции AfterFunc. Тикер после
+// срабатывания ждёт первый срок после текущего времени: пропущенные тики не копятся, как у time.Ticker
+func (s *scheduler) fire() {
+	var due []func()
+	s.mx.Lock()
+	now := s.base.Now()
+	for len(s.queue) > 0 && !s.queue[0].when.After(now) {
+		e := s.queue[0]
+		if e.f != nil {
+			due = append(due, e.f)
+		}
+		select {
+		case e.c <- e.when:
+		default:
+		}
+		if e.period == 0 {
+			heap.Pop(&s.queue)
+			continue
+		}
+		e.when = e.when.Add((now.Sub(e.when)/e.period + 1) * e.period)
+		heap.Fix(&s.queue, 0)
+	}
+	s.mx.Unlock()
+
+	// Без блокировки: функции сами переставляют и заводят таймеры
+	for _, f := range due {
+		f()
+	}
+}
+
+// entry - срок в куче. period == 0 - таймер, иначе тикер. f != nil - таймер AfterFunc, канала у него нет
+type entry struct {
+	s      *scheduler
+	c      chan time.Time
+	f      func()
+	when   time.Time
+	period time.Duration
+	// index - место в куче, -1 - срока в куче нет (сработал или остановлен)
+	index int
+}
+
+// start ставит срок через d от текущего времени. true - срок уже стоял в куче и был перенесён
+func (e *entry) start(d time.Duration) bool {
+	s := e.s
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	e.when = s.base.Now().Add(d)
+	if e.index >= 0 {
+		heap.Fix(&s.queue, e.index)
+		if e.index == 0 {
+			select {
+			case s.wake <- struct{}{}:
+			default:
+			}
+		}
+		return true
+	}
+	s.push(e)
+	return false
+}
+
+// stop убирает срок из кучи. true - срок ещё не сработал
+func (e *entry) stop() bool {
+	s := e.s
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if e.index < 0 {
+		return false
+	}
+	heap.Remove(&s.queue, e.index)
+	return true
+}
+
+type timer struct {
+	e *entry
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.e.c
+}
+
+func (t *timer) Stop() bool {
+	return t.e.stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	return t.e.start(d)
+}
+
+type ticker struct {
+	e *entry
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.e.c
+}
+
+func (t *ticker) Stop() {
+	t.e.stop()
+}
+
+// queue - куча сроков по возрастанию, реализует heap.Interface
+type queue []*entry
+
+func (q queue) Len() int {
+	return len(q)
+}
+
+func (q queue) Less(i, j int) bool {
+	return q[i].when.Before(q[j].when)
+}
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *queue) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *queue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,320 @@
+package scheduler
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+)
+
+var start = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// receive ждёт срабатывания канала. Планировщик срабатывает в своей горутине, поэтому ждём с запасом
+func receive(t *testing.T, c <-chan time.Time) time.Time {
+	t.Helper()
+	select {
+	case got := <-c:
+		return got
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+		return time.Time{}
+	}
+}
+
+func Test_scheduler_NewTimer(t *testing.T) {
+	tests := []struct {
+		name string
+		// durations - таймеры, заведённые в этом порядке
+		durations []time.Duration
+		advance   time.Duration
+	}{
+		{
+			name:      "One timer",
+			durations: []time.Duration{time.Minute},
+			advance:   time.Minute,
+		},
+		{
+			name:      "Earlier timer added later",
+			durations: []time.Duration{time.Hour, time.Minute, 30 * time.Minute},
+			advance:   time.Hour,
+		},
+		{
+			name:      "Immediately",
+			durations: []time.Duration{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					fake   = clock.NewFake(start)
+					s      = New(fake)
+					timers []clock.Timer
+				)
+				for _, d := range tt.durations {
+					timers = append(timers, s.NewTimer(d))
+				}
+				if tt.advance > 0 {
+					fake.BlockUntil(1)
+					fake.Advance(tt.advance)
+				}
+
+				for i, timer := range timers {
+					if got, want := receive(t, timer.C()), start.Add(tt.durations[i]); !got.Equal(want) {
+						t.Errorf("timer %v fired at %v, want %v", i, got, want)
+					}
+				}
+				if got := s.(*scheduler).Len(); got != 0 {
+					t.Errorf("Len() = %v, want 0", got)
+				}
+			},
+		)
+	}
+}
+
+func Test_scheduler_Timer_StopReset(t *testing.T) {
+	var (
+		fake     = clock.NewFake(start)
+		s        = New(fake)
+		stopped  = s.NewTimer(time.Minute)
+		reset    = s.NewTimer(time.Hour)
+		sentinel = s.NewTimer(time.Minute)
+	)
+	if !stopped.Stop() {
+		t.Errorf("Stop() = false, want true for active timer")
+	}
+	if stopped.Stop() {
+		t.Errorf("second Stop() = true, want false")
+	}
+	if !reset.Reset(time.Minute) {
+		t.Errorf("Reset() = false, want true for active timer")
+	}
+	if got := s.(*scheduler).Len(); got != 2 {
+		t.Errorf("Len() = %v, want 2", got)
+	}
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	receive(t, sentinel.C())
+	if got := receive(t, reset.C()); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("reset timer fired at %v, want %v", got, start.Add(time.Minute))
+	}
+	// Все сроки одного момента срабатывают за один проход, так что остановленный таймер сработал бы вместе с sentinel
+	select {
+	case <-stopped.C():
+		t.Errorf("timer fired after Stop()")
+	default:
+	}
+}
+
+func Test_scheduler_NewTicker(t *testing.T) {
+	var (
+		fake   = clock.NewFake(start)
+		s      = New(fake)
+		ticker = s.NewTicker(time.Minute)
+	)
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	if got := receive(t, ticker.C()); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("tick = %v, want %v", got, start.Add(time.Minute))
+	}
+
+	// Пропущенные тики не копятся, следующий - первый срок после текущего времени
+	fake.Advance(3 * time.Minute)
+	if got := receive(t, ticker.C()); !got.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("late tick = %v, want %v", got, start.Add(2*time.Minute))
+	}
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	if got := receive(t, ticker.C()); !got.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("tick after catch-up = %v, want %v", got, start.Add(5*time.Minute))
+	}
+
+	ticker.Stop()
+	if got := s.(*scheduler).Len(); got != 0 {
+		t.Errorf("Len() after Stop() = %v, want 0", got)
+	}
+}
+
+func Test_scheduler_AfterFunc(t *testing.T) {
+	var (
+		fake  = clock.NewFake(start)
+		s     = New(fake)
+		fired = make(chan time.Time, 2)
+		calls int
+		timer clock.Timer
+	)
+	// Функция переставляет свой же таймер - планировщик вызывает её без блокировки кучи
+	timer = s.AfterFunc(
+		time.Minute, func() {
+			if calls++; calls == 1 {
+				timer.Reset(time.Minute)
+			}
+			fired <- s.Now()
+		},
+	)
+	if timer.C() != nil {
+		t.Error("AfterFunc() timer has a channel")
+	}
+	for _, want := range []time.Time{start.Add(time.Minute), start.Add(2 * time.Minute)} {
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute)
+		if got := receive(t, fired); !got.Equal(want) {
+			t.Errorf("AfterFunc() called at %v, want %v", got, want)
+		}
+	}
+	if got := s.(*scheduler).Len(); got != 0 {
+		t.Errorf("Len() after AfterFunc() = %v, want 0", got)
+	}
+}
+
+func Test_scheduler_StopsWhenEmpty(t *testing.T) {
+	var (
+		fake  = clock.NewFake(start)
+		s     = New(fake).(*scheduler)
+		timer = s.NewTimer(time.Minute)
+	)
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	receive(t, timer.C())
+
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		s.mx.Lock()
+		running := s.running
+		s.mx.Unlock()
+		if !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduler goroutine is running with empty queue")
+		}
+	}
+
+	// Новый таймер запускает горутину снова
+	timer.Reset(time.Minute)
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	receive(t, timer.C())
+}
+
+// Вставка таймера в кучу планировщика и в рантайм: ns/op и allocs/op на одно событие
+func BenchmarkScheduler_NewTimer(b *testing.B) {
+	s := New(clock.New())
+	timers := make([]clock.Timer, 0, b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timers = append(timers, s.NewTimer(time.Hour+time.Duration(i)))
+	}
+	b.StopTimer()
+	for _, timer := range timers {
+		timer.Stop()
+	}
+}
+
+func BenchmarkStdTimer_NewTimer(b *testing.B) {
+	timers := make([]*time.Timer, 0, b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timers = append(timers, time.NewTimer(time.Hour+time.Duration(i)))
+	}
+	b.StopTimer()
+	for _, timer := range timers {
+		timer.Stop()
+	}
+}
+
+// Отмена события - O(log n) по индексу в куче
+func BenchmarkScheduler_Stop(b *testing.B) {
+	s := New(clock.New())
+	timers := make([]clock.Timer, 0, b.N)
+	for i := 0; i < b.N; i++ {
+		timers = append(timers, s.NewTimer(time.Hour+time.Duration(i)))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, timer := range timers {
+		timer.Stop()
+	}
+}
+
+// Срабатывание: все таймеры одного момента, время на одно событие
+func BenchmarkScheduler_Fire(b *testing.B) {
+	var (
+		fake   = clock.NewFake(start)
+		s      = New(fake)
+		timers = make([]clock.Timer, 0, b.N)
+	)
+	for i := 0; i < b.N; i++ {
+		timers = append(timers, s.NewTimer(time.Minute))
+	}
+	fake.BlockUntil(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	fake.Advance(time.Minute)
+	for _, timer := range timers {
+		<-timer.C()
+	}
+}
+
+// Память на одно ждущее событие: таймер в куче планировщика против прежней схемы - горутины со своим time.Timer
+func BenchmarkScheduler_Memory(b *testing.B) {
+	const events = 100_000
+	b.Run(
+		"Scheduler", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s := New(clock.New())
+				timers := make([]clock.Timer, 0, events)
+				b.ReportMetric(bytesPerEvent(events, func() {
+					for j := 0; j < events; j++ {
+						timers = append(timers, s.NewTimer(time.Hour))
+					}
+				}), "B/event")
+				for _, timer := range timers {
+					timer.Stop()
+				}
+			}
+		},
+	)
+	b.Run(
+		"GoroutineAndTimer", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var (
+					wg   sync.WaitGroup
+					stop = make(chan struct{})
+				)
+				wg.Add(events)
+				b.ReportMetric(bytesPerEvent(events, func() {
+					for j := 0; j < events; j++ {
+						go func() {
+							defer wg.Done()
+							timer := time.NewTimer(time.Hour)
+							defer timer.Stop()
+							select {
+							case <-timer.C:
+							case <-stop:
+							}
+						}()
+					}
+				}), "B/event")
+				close(stop)
+				wg.Wait()
+			}
+		},
+	)
+}
+
+// bytesPerEvent - сколько памяти (куча и стеки горутин) заняла create в пересчёте на одно событие
+func bytesPerEvent(events int, create func()) float64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	create()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	used := int64(after.HeapAlloc+after.StackInuse) - int64(before.HeapAlloc+before.StackInuse)
+	return float64(used) / float64(events)
+}
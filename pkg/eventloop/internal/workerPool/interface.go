@@ -0,0 +1,11 @@
+package workerPool
+
+import "context"
+
+type Interface interface {
+	// Submit ставит задачу в очередь пула. Что делать при полной очереди, решает OverflowPolicy пула
+	Submit(ctx context.Context, task Task) error
+	Stats() Stats
+	// Close останавливает воркеры после выполнения текущих задач. Задачи, оставшиеся в очереди, отменяются с ErrClosed
+	Close()
+}
@@ -0,0 +1,187 @@
+package workerPool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy - что делать с новой задачей, если очередь пула заполнена
+type OverflowPolicy string
+
+const (
+	// Block - ждать, пока в очереди освободится место или закончится контекст (по умолчанию)
+	Block OverflowPolicy = "BLOCK"
+	// DropNewest - отбросить новую задачу
+	DropNewest OverflowPolicy = "DROP_NEWEST"
+	// DropOldest - отбросить самую старую задачу из очереди и поставить новую
+	DropOldest OverflowPolicy = "DROP_OLDEST"
+	// Reject - не принимать новую задачу, Submit возвращает ErrRejected
+	Reject OverflowPolicy = "REJECT"
+)
+
+var (
+	ErrDropped  = errors.New("task dropped, worker pool queue is full")
+	ErrRejected = errors.New("task rejected, worker pool queue is full")
+	ErrClosed   = errors.New("worker pool is closed")
+)
+
+// Task - задача для пула. Run выполняется воркером. Drop вызывается вместо Run, если задача была принята в очередь, но
+// выполнена не будет (DropOldest, Close)
+type Task struct {
+	Run  func()
+	Drop func(err error)
+}
+
+// Stats - состояние пула для мониторинга
+type Stats struct {
+	Workers    int
+	Busy       int
+	QueueDepth int
+	QueueSize  int
+	Dropped    uint64
+	Rejected   uint64
+}
+
+// pool - пул с фиксированным числом воркеров и ограниченной очередью задач
+type pool struct {
+	queue    chan Task
+	overflow OverflowPolicy
+	workers  int
+
+	busy     int32
+	dropped  uint64
+	rejected uint64
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func New(workers, queueSize int, overflow OverflowPolicy) Interface {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	if overflow == "" {
+		overflow = Block
+	}
+	p := &pool{
+		queue:    make(chan Task, queueSize),
+		overflow: overflow,
+		workers:  workers,
+		closed:   make(chan struct{}),
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *pool) worker() {
+	defer p.wg.Done()
+	for {
+		// Закрытие пула важнее задач в очереди
+		select {
+		case <-p.closed:
+			return
+		default:
+		}
+		select {
+		case <-p.closed:
+			return
+		case task := <-p.queue:
+			atomic.AddInt32(&p.busy, 1)
+			task.Run()
+			atomic.AddInt32(&p.busy, -1)
+		}
+	}
+}
+
+func (p *pool) Submit(ctx context.Context, task Task) error {
+	select {
+	case <-p.closed:
+		return ErrClosed
+	default:
+	}
+
+	switch p.overflow {
+	case DropNewest, Reject:
+		select {
+		case p.queue <- task:
+			return nil
+		default:
+			if p.overflow == Reject {
+				atomic.AddUint64(&p.rejected, 1)
+				return ErrRejected
+			}
+			atomic.AddUint64(&p.dropped, 1)
+			return ErrDropped
+		}
+	case DropOldest:
+		for {
+			select {
+			case p.queue <- task:
+				return nil
+			default:
+			}
+			// Очередь полна - освобождаем место, выкидывая самую старую задачу
+			select {
+			case oldest := <-p.queue:
+				atomic.AddUint64(&p.dropped, 1)
+				if oldest.Drop != nil {
+					oldest.Drop(ErrDropped)
+				}
+			default:
+				// Без очереди выкидывать нечего, отбрасываем саму задачу
+				if cap(p.queue) == 0 {
+					atomic.AddUint64(&p.dropped, 1)
+					return ErrDropped
+				}
+			}
+		}
+	default:
+		select {
+		case p.queue <- task:
+			return nil
+		case <-p.closed:
+			return ErrClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *pool) Stats() Stats {
+	return Stats{
+		Workers:    p.workers,
+		Busy:       int(atomic.LoadInt32(&p.busy)),
+		QueueDepth: len(p.queue),
+		QueueSize:  cap(p.queue),
+		Dropped:    atomic.LoadUint64(&p.dropped),
+		Rejected:   atomic.LoadUint64(&p.rejected),
+	}
+}
+
+func (p *pool) Close() {
+	p.closeOnce.Do(
+		func() {
+			close(p.closed)
+			p.wg.Wait()
+			for {
+				select {
+				case task := <-p.queue:
+					if task.Drop != nil {
+						task.Drop(ErrClosed)
+					}
+				default:
+					return
+				}
+			}
+		},
+	)
+}
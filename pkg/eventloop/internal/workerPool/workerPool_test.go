@@ -0,0 +1,217 @@
+package workerPool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockWorkers занимает все воркеры пула задачами, которые ждут закрытия release
+func blockWorkers(t *testing.T, p Interface, workers int) (release chan struct{}) {
+	release = make(chan struct{})
+	started := sync.WaitGroup{}
+	started.Add(workers)
+	for i := 0; i < workers; i++ {
+		if err := p.Submit(
+			context.Background(),
+			Task{
+				Run: func() {
+					started.Done()
+					<-release
+				},
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	started.Wait()
+	return release
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		workers   int
+		queueSize int
+		want      Stats
+	}{
+		{
+			name:      "Default",
+			workers:   4,
+			queueSize: 10,
+			want:      Stats{Workers: 4, QueueSize: 10},
+		},
+		{
+			name:      "Normalized",
+			workers:   0,
+			queueSize: -1,
+			want:      Stats{Workers: 1, QueueSize: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				p := New(tt.workers, tt.queueSize, "")
+				defer p.Close()
+				if got := p.Stats(); got != tt.want {
+					t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_pool_Submit(t *testing.T) {
+	tests := []struct {
+		name         string
+		overflow     OverflowPolicy
+		wantErr      error
+		wantDropped  uint64
+		wantRejected uint64
+		// wantOldestDropped - первая задача из очереди отброшена, вместо неё выполнится новая
+		wantOldestDropped bool
+	}{
+		{
+			name:        "Drop newest",
+			overflow:    DropNewest,
+			wantErr:     ErrDropped,
+			wantDropped: 1,
+		},
+		{
+			name:              "Drop oldest",
+			overflow:          DropOldest,
+			wantDropped:       1,
+			wantOldestDropped: true,
+		},
+		{
+			name:         "Reject",
+			overflow:     Reject,
+			wantErr:      ErrRejected,
+			wantRejected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					p          = New(1, 1, tt.overflow)
+					release    = blockWorkers(t, p, 1)
+					oldestErr  error
+					oldestDone = make(chan struct{})
+					newestDone = make(chan struct{})
+				)
+				defer p.Close()
+
+				if err := p.Submit(
+					context.Background(),
+					Task{
+						Run: func() { close(oldestDone) },
+						Drop: func(err error) {
+							oldestErr = err
+							close(oldestDone)
+						},
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+				if depth := p.Stats().QueueDepth; depth != 1 {
+					t.Errorf("Stats().QueueDepth = %v, want 1", depth)
+				}
+
+				err := p.Submit(
+					context.Background(),
+					Task{
+						Run: func() {
+							close(newestDone)
+						},
+					},
+				)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Submit() error = %v, want %v", err, tt.wantErr)
+				}
+
+				close(release)
+				<-oldestDone
+				if tt.wantOldestDropped {
+					<-newestDone
+				}
+				if got := errors.Is(oldestErr, ErrDropped); got != tt.wantOldestDropped {
+					t.Errorf("oldest task dropped = %v, want %v", got, tt.wantOldestDropped)
+				}
+				if got := p.Stats(); got.Dropped != tt.wantDropped || got.Rejected != tt.wantRejected {
+					t.Errorf(
+						"Stats() dropped = %v, rejected = %v, want %v, %v",
+						got.Dropped, got.Rejected, tt.wantDropped, tt.wantRejected,
+					)
+				}
+			},
+		)
+	}
+}
+
+func Test_pool_Submit_Block(t *testing.T) {
+	var (
+		p       = New(1, 0, Block)
+		release = blockWorkers(t, p, 1)
+		done    = make(chan struct{})
+	)
+	defer p.Close()
+
+	go func() {
+		_ = p.Submit(context.Background(), Task{Run: func() {}})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Submit() returned while all workers are busy")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-done
+}
+
+func Test_pool_Submit_BlockContextDone(t *testing.T) {
+	var (
+		p           = New(1, 0, Block)
+		release     = blockWorkers(t, p, 1)
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	)
+	defer p.Close()
+	defer close(release)
+	defer cancel()
+
+	if err := p.Submit(ctx, Task{Run: func() {}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_pool_Close(t *testing.T) {
+	var (
+		p       = New(1, 1, Block)
+		release = blockWorkers(t, p, 1)
+		dropErr = make(chan error, 1)
+	)
+	queued := Task{Run: func() {}, Drop: func(err error) { dropErr <- err }}
+	if err := p.Submit(context.Background(), queued); err != nil {
+		t.Fatal(err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	<-p.(*pool).closed
+	close(release)
+	<-closed
+
+	if err := <-dropErr; !errors.Is(err, ErrClosed) {
+		t.Errorf("queued task dropped with %v, want %v", err, ErrClosed)
+	}
+	if err := p.Submit(context.Background(), Task{Run: func() {}}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit() after Close() error = %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,265 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/interval"
+	loggerEventLoop "gitlab.com/YSX/eventloop/pkg/logger"
+	"golang.org/x/exp/maps"
+)
+
+var (
+	ErrNoEvent     = errors.New("no such event")
+	ErrNotInterval = errors.New("event is not an interval event")
+)
+
+// StartInterval запускает одно интервальное событие, не трогая остальные. Уже запущенный интервал продолжает идти
+func (e *eventLoop) StartInterval(uUID string) error {
+	ev, err := e.intervalEvent(uUID)
+	if err != nil {
+		return err
+	}
+	if e.isShutdown() {
+		return ErrShutdown
+	}
+	e.startIntervalEvent(context.Background(), ev)
+	return nil
+}
+
+// StopInterval останавливает одно интервальное событие, не трогая остальные. Событие остаётся в менеджере, его можно
+// запустить снова
+func (e *eventLoop) StopInterval(uUID string) error {
+	ev, err := e.intervalEvent(uUID)
+	if err != nil {
+		return err
+	}
+	e.stopIntervalEvent(ev)
+	return nil
+}
+
+func (e *eventLoop) intervalEvent(uUID string) (event.Interface, error) {
+	events, _ := e.eventsByUUIDs(uUID)
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrNoEvent, uUID)
+	}
+	if _, err := events[0].Interval(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotInterval, uUID)
+	}
+	return events[0], nil
+}
+
+// startIntervalEvent запускает интервал, если он ещё не запущен, и выполняет системные события INTERVAL_START.
+// Интервал живёт, пока не закончится ctx, его не остановят или не остановят менеджер событий. false - интервал уже был
+// запущен
+func (e *eventLoop) startIntervalEvent(ctx context.Context, ev event.Interface) bool {
+	intervalComponent, err := ev.Interval()
+	if err != nil || ctx.Err() != nil {
+		return false
+	}
+	e.intervalMx.Lock()
+	// Shutdown останавливает интервалы под intervalMx, после него новые не запускаются
+	if intervalComponent.IsRunning() || e.isShutdown() {
+		e.intervalMx.Unlock()
+		return false
+	}
+	e.logger.Debugw("Run scheduled", "eventId", ev.GetUUID())
+	intervalComponent.SetRunning(true)
+	run := e.newIntervalRun(ctx, ev)
+	if e.intervals == nil {
+		e.intervals = make(map[string]*intervalRun)
+	}
+	e.intervals[ev.GetUUID()] = run
+	e.intervalMx.Unlock()
+
+	// Контекст без Done (context.Background) не заканчивается, следить за ним не нужно
+	if ctx.Done() != nil {
+		go func() {
+			<-run.ctx.Done()
+			e.endInterval(run)
+		}()
+	}
+	_ = e.lifecycle(ctx, eventLifecycle(INTERVAL_START, ev))
+	return true
+}
+
+// stopIntervalEvent останавливает интервал и выполняет системные события INTERVAL_STOP. После возврата новых
+// запусков нет, уже идущие доработают. false - интервал не был запущен или уже завершился сам
+func (e *eventLoop) stopIntervalEvent(ev event.Interface) bool {
+	e.intervalMx.Lock()
+	run := e.intervals[ev.GetUUID()]
+	e.intervalMx.Unlock()
+	if run == nil || !e.endInterval(run) {
+		return false
+	}
+
+	_ = e.lifecycle(context.Background(), eventLifecycle(INTERVAL_STOP, ev))
+	return true
+}
+
+// stopIntervals останавливает все запущенные интервалы без событий INTERVAL_STOP. Нужна при Shutdown
+func (e *eventLoop) stopIntervals() {
+	e.intervalMx.Lock()
+	runs := maps.Values(e.intervals)
+	e.intervalMx.Unlock()
+	for _, run := range runs {
+		e.endInterval(run)
+	}
+}
+
+// endInterval останавливает запуск интервала и отмечает интервал остановленным. false - run уже остановлен
+func (e *eventLoop) endInterval(run *intervalRun) bool {
+	e.intervalMx.Lock()
+	defer e.intervalMx.Unlock()
+	if !run.stop() {
+		return false
+	}
+	delete(e.intervals, run.ev.GetUUID())
+	run.component.SetRunning(false)
+	e.logger.Infow("Interval event stopped", "ev", run.ev.GetUUID())
+	return true
+}
+
+// intervalRun - запущенный интервал. Своей горутины у него нет: тики приходят функцией AfterFunc планировщика, а
+// функция события выполняется через spawn
+type intervalRun struct {
+	e         *eventLoop
+	ev        event.Interface
+	component interval.Interface
+	// ctx - контекст запусков функции, отменяется при остановке интервала
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mx      sync.Mutex
+	timer   clock.Timer
+	stopped bool
+	// scheduled - время, на которое назначен следующий запуск без учёта джиттера. По нему видно, что запуски опоздали
+	// или потерялись
+	scheduled time.Time
+	jitter    time.Duration
+	// running - выполняющиеся запуски функции, завершившееся по MaxRuns событие дожидается их перед удалением
+	running sync.WaitGroup
+}
+
+// newIntervalRun заводит таймер первого запуска интервала
+func (e *eventLoop) newIntervalRun(ctx context.Context, ev event.Interface) *intervalRun {
+	intervalComponent, _ := ev.Interval()
+	run := &intervalRun{e: e, ev: ev, component: intervalComponent}
+	run.ctx, run.cancel = context.WithCancel(loggerEventLoop.WithLogger(ctx, e.logger))
+	run.scheduled = intervalComponent.FirstRun(e.clock.Now())
+	run.jitter = intervalComponent.Jitter()
+	e.logger.Infow(
+		"Scheduled ev starting with interval",
+		"ev", ev.GetUUID(),
+		"interval", intervalComponent.GetDuration(),
+		"first", run.scheduled,
+	)
+
+	// Задержки таймера всегда положительные, поэтому tick не вызовется, пока run.mx занят здесь
+	run.mx.Lock()
+	defer run.mx.Unlock()
+	run.timer = e.timers().AfterFunc(run.scheduled.Add(run.jitter).Sub(e.clock.Now()), run.tick)
+	return run
+}
+
+// stop снимает таймер и отменяет контекст запусков. false - интервал уже остановлен
+func (r *intervalRun) stop() bool {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if r.stopped {
+		return false
+	}
+	r.stopped = true
+	r.timer.Stop()
+	r.cancel()
+	return true
+}
+
+// tick - срабатывание таймера интервала в горутине планировщика. Переставляет таймер на следующий запуск и отдаёт
+// функцию события в spawn
+func (r *intervalRun) tick() {
+	e, ev := r.e, r.ev
+	if r.ctx.Err() != nil {
+		e.endInterval(r)
+		return
+	}
+
+	period := r.component.GetDuration()
+	r.mx.Lock()
+	if r.stopped {
+		r.mx.Unlock()
+		return
+	}
+	now := e.clock.Now()
+	runs := missedRuns(
+		ev.GetMisfire(), r.scheduled.Add(r.jitter), now, func(slot time.Time) time.Time {
+			return slot.Add(period)
+		},
+	)
+	lateBy := now.Sub(r.scheduled.Add(r.jitter))
+	r.scheduled = r.scheduled.Add(period)
+	if late := now.Sub(r.scheduled); late >= 0 {
+		r.scheduled = r.scheduled.Add((late/period + 1) * period)
+	}
+	r.jitter = r.component.Jitter()
+	r.timer.Reset(r.scheduled.Add(r.jitter).Sub(now))
+	r.mx.Unlock()
+
+	// У пропущенного запуска и запуска на паузе нет ошибки, handleResult для них ничего не ждёт
+	if len(runs) == 0 {
+		e.handleResult(r.ctx, e.missedResult(ev, lateBy))
+		return
+	}
+	if ev.IsPaused() {
+		e.handleResult(r.ctx, e.pausedResult(ev))
+		return
+	}
+	if !r.component.StartRun() {
+		e.logger.Debugw("Interval tick skipped, previous run is still executing", "ev", ev.GetUUID())
+		return
+	}
+	allowed, last := r.component.TakeRuns(len(runs))
+	runs = runs[:allowed]
+	r.running.Add(1)
+	e.spawn(
+		r.ctx, func(ctx context.Context) (result event.Result) {
+			defer r.running.Done()
+			defer r.component.FinishRun()
+			for _, lateBy := range runs {
+				result = e.runLate(ctx, ev, lateBy)
+				e.handleResult(ctx, result)
+				// Функция, брошенная по таймауту, ещё выполняется - OverlapSkip не должен запускать следующую
+				waitTimedOut(result)
+				if once, onceErr := ev.Once(); onceErr == nil {
+					once.Do(
+						func() {
+							e.endInterval(r)
+						},
+					)
+					return result
+				}
+			}
+			return result
+		}, func(err error) {
+			defer r.running.Done()
+			defer r.component.FinishRun()
+			e.handleResult(r.ctx, e.notExecutedResult(ev, err))
+		},
+	)
+	if last {
+		r.mx.Lock()
+		r.timer.Stop()
+		r.mx.Unlock()
+		go func() {
+			r.running.Wait()
+			e.logger.Infow("Interval event reached max runs, removing", "ev", ev.GetUUID())
+			e.removeEvents(ev.GetUUID())
+			e.endInterval(r)
+		}()
+	}
+}
@@ -0,0 +1,227 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/interval"
+)
+
+func Test_eventLoop_StartInterval_Errors(t *testing.T) {
+	var (
+		e     = NewEventLoop(WithLogger(newTestLogger()))
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Regular",
+				Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		uUID    string
+		wantErr error
+	}{
+		{
+			name:    "No event",
+			uUID:    "missing",
+			wantErr: ErrNoEvent,
+		},
+		{
+			name:    "Not interval",
+			uUID:    ev.GetUUID(),
+			wantErr: ErrNotInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if err := e.StartInterval(tt.uUID); !errors.Is(err, tt.wantErr) {
+					t.Errorf("StartInterval() error = %v, want %v", err, tt.wantErr)
+				}
+				if err := e.StopInterval(tt.uUID); !errors.Is(err, tt.wantErr) {
+					t.Errorf("StopInterval() error = %v, want %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_StartStopInterval(t *testing.T) {
+	var (
+		fake = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+		e    = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		ctx  = context.Background()
+		runs = make(chan string, 10)
+
+		newInterval = func(name string, options interval.Options) event.Interface {
+			ev, _ := event.NewEvent(
+				event.Args{
+					TriggerName:     string(INTERVALED),
+					IntervalTime:    time.Minute,
+					IntervalOptions: options,
+					Fun: func(ctx context.Context) string {
+						runs <- name
+						return name
+					},
+				},
+			)
+			return ev
+		}
+		first  = newInterval("first", interval.Options{})
+		second = newInterval("second", interval.Options{})
+		auto   = newInterval("auto", interval.Options{AutoStart: true})
+	)
+	defer e.CancelEvent(first.GetUUID(), second.GetUUID(), auto.GetUUID())
+	if err := e.RegisterEvent(ctx, first, second, auto); err != nil {
+		t.Fatal(err)
+	}
+
+	// tick ждёт, пока все running интервалов заведут таймеры, сдвигает часы на интервал и собирает, какие интервалы
+	// сработали
+	tick := func(running int) map[string]bool {
+		timers := e.(*eventLoop).scheduler.(interface{ Len() int })
+		for deadline := time.Now().Add(time.Second); timers.Len() < running; time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%v intervals are waiting, want %v", timers.Len(), running)
+			}
+		}
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute)
+		got := make(map[string]bool)
+		for i := 0; i < running; i++ {
+			got[<-runs] = true
+		}
+		return got
+	}
+
+	if got := tick(1); !got["auto"] {
+		t.Errorf("runs = %v, want auto interval started on registration", got)
+	}
+
+	if err := e.StartInterval(first.GetUUID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.StartInterval(first.GetUUID()); err != nil {
+		t.Errorf("second StartInterval() error = %v, want nil", err)
+	}
+	if got := tick(2); !got["auto"] || !got["first"] {
+		t.Errorf("runs = %v, want auto and first", got)
+	}
+
+	if err := e.StartInterval(second.GetUUID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.StopInterval(first.GetUUID()); err != nil {
+		t.Fatal(err)
+	}
+	firstInterval, _ := first.Interval()
+	for deadline := time.Now().Add(time.Second); firstInterval.IsRunning(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("interval is still running after StopInterval()")
+		}
+	}
+	if got := tick(2); !got["auto"] || !got["second"] {
+		t.Errorf("runs = %v, want auto and second", got)
+	}
+	select {
+	case got := <-runs:
+		t.Errorf("unexpected run of %v", got)
+	default:
+	}
+}
+
+// Запущенные интервалы: память на один интервал, время запуска и время одного тика в пересчёте на событие. Тики
+// приходят функциями планировщика, своих горутин у интервалов нет
+func BenchmarkEventLoop_Interval(b *testing.B) {
+	const events = 10_000
+	var (
+		ctx     = context.Background()
+		newLoop = func(b *testing.B, c clock.Interface, n int, fun event.Func) (Interface, []event.Interface) {
+			e := NewEventLoop(WithClock(c))
+			events := make([]event.Interface, 0, n)
+			for i := 0; i < n; i++ {
+				ev, err := event.NewEvent(event.Args{IntervalTime: time.Minute, Fun: fun})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err = e.RegisterEvent(ctx, ev); err != nil {
+					b.Fatal(err)
+				}
+				events = append(events, ev)
+			}
+			return e, events
+		}
+		// start запускает интервалы в обход StartInterval: поиск события по UUID не относится к интервалам
+		start = func(b *testing.B, e Interface, events []event.Interface) {
+			for _, ev := range events {
+				if !e.(*eventLoop).startIntervalEvent(ctx, ev) {
+					b.Fatalf("interval %v is not started", ev.GetUUID())
+				}
+			}
+		}
+		nop = func(ctx context.Context) string {
+			return ""
+		}
+	)
+	b.Run(
+		"Memory", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				e, intervals := newLoop(b, clock.New(), events, nop)
+				b.ReportMetric(
+					bytesPerEvent(
+						events, func() {
+							start(b, e, intervals)
+						},
+					), "B/event",
+				)
+				_, _ = e.Shutdown(ctx)
+			}
+		},
+	)
+	b.Run(
+		"Start", func(b *testing.B) {
+			e, intervals := newLoop(b, clock.New(), b.N, nop)
+			b.ReportAllocs()
+			b.ResetTimer()
+			start(b, e, intervals)
+			b.StopTimer()
+			_, _ = e.Shutdown(ctx)
+		},
+	)
+	b.Run(
+		"Tick", func(b *testing.B) {
+			var (
+				fake = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+				runs sync.WaitGroup
+			)
+			e, intervals := newLoop(
+				b, fake, b.N, func(ctx context.Context) string {
+					runs.Done()
+					return ""
+				},
+			)
+			start(b, e, intervals)
+			runs.Add(b.N)
+			fake.BlockUntil(1)
+			b.ReportAllocs()
+			b.ResetTimer()
+			fake.Advance(time.Minute)
+			runs.Wait()
+			b.StopTimer()
+			_, _ = e.Shutdown(ctx)
+		},
+	)
+}
@@ -0,0 +1,139 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+	loggerEventLoop "gitlab.com/YSX/eventloop/pkg/logger"
+	"golang.org/x/exp/slices"
+)
+
+// ErrVetoed - операцию отменило системное событие BEFORE_ стадии жизненного цикла (см. VetoError)
+var ErrVetoed = errors.New("vetoed by lifecycle hook")
+
+// Lifecycle - описание операции менеджера событий. Его получают функции системных событий жизненного цикла
+// (BEFORE_CREATE, AFTER_TRIGGER, ON_ERROR...) через LifecycleFrom
+type Lifecycle struct {
+	Stage eventLoopSystemTrigger
+	// TriggerName - вызываемый триггер или триггер создаваемого, удаляемого, запускаемого события
+	TriggerName string
+	Namespace   string
+	// Events - UUID событий операции: создаваемого, удаляемых, событий вызываемого триггера, интервала
+	Events []string
+	// TriggerResult - результат вызова триггера, только для AFTER_TRIGGER
+	TriggerResult *TriggerResult
+	// Result - результат выполнения с ошибкой, только для ON_ERROR
+	Result *event.Result
+}
+
+type lifecycleCtxKey struct{}
+
+// LifecycleFrom возвращает описание операции, на которую вызвано системное событие жизненного цикла. ok == false -
+// функция вызвана не как событие жизненного цикла
+func LifecycleFrom(ctx context.Context) (lifecycle Lifecycle, ok bool) {
+	lifecycle, ok = ctx.Value(lifecycleCtxKey{}).(Lifecycle)
+	return
+}
+
+// VetoError - событие Hook стадии Stage завершилось ошибкой Err и отменило операцию. errors.Is(err, ErrVetoed) == true
+type VetoError struct {
+	Stage eventLoopSystemTrigger
+	Hook  string
+	Err   error
+}
+
+func (ve *VetoError) Error() string {
+	return fmt.Sprintf("%v %v (event %v): %v", ve.Stage, ErrVetoed, ve.Hook, ve.Err)
+}
+
+func (ve *VetoError) Is(target error) bool {
+	return target == ErrVetoed
+}
+
+func (ve *VetoError) Unwrap() error {
+	return ve.Err
+}
+
+// vetoStages - стадии, события которых могут отменить операцию, вернув ошибку (или event.ErrStop)
+var vetoStages = []eventLoopSystemTrigger{BEFORE_CREATE, BEFORE_REMOVE, BEFORE_TRIGGER}
+
+// lifecycle выполняет системные события стадии lc.Stage по убыванию приоритета, одно за другим. На стадиях из
+// vetoStages первое событие с ошибкой отменяет операцию: остальные события не выполняются, возвращается *VetoError.
+// Вызывающий не должен держать e.mx
+func (e *eventLoop) lifecycle(ctx context.Context, lc Lifecycle) error {
+	e.mx.RLock()
+	hooks := e.events.GetPrioritySortedEventsByTrigger(string(lc.Stage))
+	e.mx.RUnlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	ctx = context.WithValue(loggerEventLoop.WithLogger(ctx, e.logger), lifecycleCtxKey{}, lc)
+	for _, hook := range hooks {
+		if hook.IsPaused() {
+			continue
+		}
+		result := e.execute(ctx, hook)
+		if lc.Stage == ON_ERROR {
+			// Ошибки событий ON_ERROR только логируются, иначе они вызывали бы сами себя
+			if result.Err != nil {
+				e.logger.Errorw("ON_ERROR event function error", "eventId", result.UUID, "error", result.Err)
+			}
+			continue
+		}
+		result = e.handleResult(ctx, result)
+		if result.Err != nil && slices.Contains(vetoStages, lc.Stage) {
+			e.logger.Infow("Operation vetoed", "stage", lc.Stage, "eventId", result.UUID, "error", result.Err)
+			return &VetoError{Stage: lc.Stage, Hook: result.UUID, Err: result.Err}
+		}
+	}
+	return nil
+}
+
+// eventLifecycle - описание операции над одним событием
+func eventLifecycle(stage eventLoopSystemTrigger, ev event.Interface) Lifecycle {
+	return Lifecycle{
+		Stage:       stage,
+		TriggerName: ev.GetTriggerName(),
+		Namespace:   ev.GetNamespace(),
+		Events:      []string{ev.GetUUID()},
+	}
+}
+
+// removeLifecycle - описание удаления событий. Системные события в него не попадают, если удаляются только они,
+// Lifecycle.Events пустой и события жизненного цикла не вызываются
+func removeLifecycle(events []event.Interface) Lifecycle {
+	lc := Lifecycle{Stage: BEFORE_REMOVE}
+	for _, ev := range events {
+		if !isSystemEvent(ev) {
+			lc.Events = append(lc.Events, ev.GetUUID())
+		}
+	}
+	if len(lc.Events) == 1 && len(events) == 1 {
+		lc = eventLifecycle(BEFORE_REMOVE, events[0])
+	}
+	return lc
+}
+
+// triggerLifecycle - описание вызова триггера по его ключу в контейнере событий
+func triggerLifecycle(stage eventLoopSystemTrigger, triggerKey string, events []event.Interface) Lifecycle {
+	namespace, triggerName := eventsContainer.SplitTriggerKey(triggerKey)
+	return Lifecycle{Stage: stage, TriggerName: triggerName, Namespace: namespace, Events: eventUUIDs(events)}
+}
+
+func eventUUIDs(events []event.Interface) []string {
+	result := make([]string, 0, len(events))
+	for _, ev := range events {
+		result = append(result, ev.GetUUID())
+	}
+	return result
+}
+
+// isSystemEvent - событие системного триггера. Их регистрация и удаление не вызывают события жизненного цикла, чтобы
+// события одной стадии не вызывали друг друга
+func isSystemEvent(ev event.Interface) bool {
+	return slices.Contains(allSystemTriggers, eventLoopSystemTrigger(ev.GetTriggerName()))
+}
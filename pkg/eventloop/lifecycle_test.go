@@ -0,0 +1,208 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+func Test_eventLoop_Lifecycle(t *testing.T) {
+	var (
+		e   = NewEventLoop(WithLogger(newTestLogger()))
+		ctx = context.Background()
+		mx  sync.Mutex
+		// got - описания операций, полученные событиями жизненного цикла, по порядку
+		got     []Lifecycle
+		errFail = errors.New("fail")
+	)
+	for _, stage := range []eventLoopSystemTrigger{
+		BEFORE_CREATE, AFTER_CREATE, BEFORE_REMOVE, AFTER_REMOVE, BEFORE_TRIGGER, AFTER_TRIGGER, ON_ERROR,
+		INTERVAL_START, INTERVAL_STOP,
+	} {
+		hook, _ := event.NewEvent(
+			event.Args{
+				TriggerName: string(stage),
+				Fun: func(ctx context.Context) string {
+					lc, ok := LifecycleFrom(ctx)
+					if !ok {
+						t.Error("LifecycleFrom() ok = false")
+					}
+					mx.Lock()
+					got = append(got, lc)
+					mx.Unlock()
+					return ""
+				},
+			},
+		)
+		if err := e.RegisterEvent(ctx, hook); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var (
+		failing, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig",
+				ErrFun: func(ctx context.Context) (string, error) {
+					return "", errFail
+				},
+			},
+		)
+		intervalEv, _ = event.NewEvent(
+			event.Args{
+				IntervalTime: time.Hour,
+				Fun: func(ctx context.Context) string {
+					return ""
+				},
+			},
+		)
+		one = []string{failing.GetUUID()}
+	)
+
+	if err := e.RegisterEvent(ctx, failing); err != nil {
+		t.Fatal(err)
+	}
+	result, err := e.Trigger(ctx, "Trig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := e.RemoveEventByUUIDs(failing.GetUUID()); len(missing) != 0 {
+		t.Fatalf("RemoveEventByUUIDs() = %v", missing)
+	}
+	if err = e.RegisterEvent(ctx, intervalEv); err != nil {
+		t.Fatal(err)
+	}
+	if err = e.StartInterval(intervalEv.GetUUID()); err != nil {
+		t.Fatal(err)
+	}
+	if err = e.StopInterval(intervalEv.GetUUID()); err != nil {
+		t.Fatal(err)
+	}
+
+	errResult := result.Events[0]
+	want := []Lifecycle{
+		{Stage: BEFORE_CREATE, TriggerName: "Trig", Events: one},
+		{Stage: AFTER_CREATE, TriggerName: "Trig", Events: one},
+		{Stage: BEFORE_TRIGGER, TriggerName: "Trig", Events: one},
+		{Stage: ON_ERROR, Events: one, Result: &errResult},
+		{Stage: AFTER_TRIGGER, TriggerName: "Trig", Events: one, TriggerResult: &result},
+		{Stage: BEFORE_REMOVE, TriggerName: "Trig", Events: one},
+		{Stage: AFTER_REMOVE, TriggerName: "Trig", Events: one},
+		{Stage: BEFORE_CREATE, Events: []string{intervalEv.GetUUID()}},
+		{Stage: AFTER_CREATE, Events: []string{intervalEv.GetUUID()}},
+		{Stage: INTERVAL_START, Events: []string{intervalEv.GetUUID()}},
+		{Stage: INTERVAL_STOP, Events: []string{intervalEv.GetUUID()}},
+	}
+	mx.Lock()
+	defer mx.Unlock()
+	if len(got) != len(want) {
+		t.Fatalf("lifecycle stages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("lifecycle %v = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_eventLoop_Lifecycle_Veto(t *testing.T) {
+	errForbidden := errors.New("forbidden")
+	tests := []struct {
+		name  string
+		stage eventLoopSystemTrigger
+		// do выполняет операцию над зарегистрированным событием ev и возвращает ошибку отмены
+		do func(e Interface, ev event.Interface) error
+		// wantRegistered - событие осталось в менеджере
+		wantRegistered bool
+	}{
+		{
+			name:  "Create",
+			stage: BEFORE_CREATE,
+			do: func(e Interface, ev event.Interface) error {
+				ev2, _ := event.NewEvent(
+					event.Args{
+						TriggerName: "Trig",
+						Fun: func(ctx context.Context) string {
+							return ""
+						},
+					},
+				)
+				return e.RegisterEvent(context.Background(), ev2)
+			},
+			wantRegistered: true,
+		},
+		{
+			name:  "Remove",
+			stage: BEFORE_REMOVE,
+			do: func(e Interface, ev event.Interface) error {
+				if missing := e.RemoveEventByUUIDs(ev.GetUUID()); len(missing) != 1 {
+					return errors.New("vetoed event is reported as removed")
+				}
+				if missing := e.CancelEvent(ev.GetUUID()); len(missing) != 1 {
+					return errors.New("vetoed event is reported as cancelled")
+				}
+				if missing := e.RemoveTriggers("Trig"); len(missing) != 1 {
+					return errors.New("vetoed trigger is reported as removed")
+				}
+				return ErrVetoed
+			},
+			wantRegistered: true,
+		},
+		{
+			name:  "Trigger",
+			stage: BEFORE_TRIGGER,
+			do: func(e Interface, ev event.Interface) error {
+				result, err := e.Trigger(context.Background(), "Trig")
+				if len(result.Events) != 0 {
+					return errors.New("vetoed trigger ran events")
+				}
+				return err
+			},
+			wantRegistered: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					e     = NewEventLoop(WithLogger(newTestLogger()))
+					ctx   = context.Background()
+					ev, _ = event.NewEvent(
+						event.Args{
+							TriggerName: "Trig",
+							Fun: func(ctx context.Context) string {
+								return ""
+							},
+						},
+					)
+					veto, _ = event.NewEvent(
+						event.Args{
+							TriggerName: string(tt.stage),
+							ErrFun: func(ctx context.Context) (string, error) {
+								return "", errForbidden
+							},
+						},
+					)
+				)
+				if err := e.RegisterEvent(ctx, ev, veto); err != nil {
+					t.Fatal(err)
+				}
+				err := tt.do(e, ev)
+				if !errors.Is(err, ErrVetoed) {
+					t.Errorf("error = %v, want %v", err, ErrVetoed)
+				}
+				if tt.stage != BEFORE_REMOVE && !errors.Is(err, errForbidden) {
+					t.Errorf("error = %v, want it to wrap hook error", err)
+				}
+				registered := len(e.GetAttachedEvents("Trig")) == 1
+				if registered != tt.wantRegistered {
+					t.Errorf("event registered = %v, want %v", registered, tt.wantRegistered)
+				}
+			},
+		)
+	}
+}
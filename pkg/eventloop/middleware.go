@@ -0,0 +1,62 @@
+package eventloop
+
+import (
+	"context"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+)
+
+// Handler - выполнение события: вызов его функции (со всеми повторами политики event.Args.Retry) и результат
+type Handler func(ctx context.Context, ev event.Interface) event.Result
+
+// Middleware оборачивает выполнение события. Она может изменить ctx (трассировка), проверить событие и payload и не
+// вызывать next, вернув результат с ошибкой (авторизация, валидация), или посмотреть на результат next (метрики)
+type Middleware func(next Handler) Handler
+
+// Use добавляет middleware для выполнения всех событий: по триггеру, интервальных, cron, AFTER и слушателей. Системные
+// события и события на паузе через middleware не проходят. Первая добавленная middleware - внешняя
+func (e *eventLoop) Use(middleware ...Middleware) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	e.middleware = append(e.middleware, middleware...)
+}
+
+// UseTrigger добавляет middleware только для событий триггера triggerName (для событий шаблона - его имя, например
+// order.*). Они выполняются внутри общих middleware из Use, в порядке добавления. Для зарезервированных имён с
+// префиксом event.NamespacePrefix возвращает event.ErrReservedTriggerName и middleware не добавляет
+func (e *eventLoop) UseTrigger(triggerName string, middleware ...Middleware) error {
+	if err := event.ValidateTriggerName(triggerName); err != nil {
+		return err
+	}
+	e.useTrigger(triggerName, middleware...)
+	return nil
+}
+
+// useTrigger добавляет middleware по ключу триггера в контейнере, в том числе триггера пространства имён
+func (e *eventLoop) useTrigger(triggerName string, middleware ...Middleware) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.triggerMiddleware == nil {
+		e.triggerMiddleware = make(map[string][]Middleware)
+	}
+	e.triggerMiddleware[triggerName] = append(e.triggerMiddleware[triggerName], middleware...)
+}
+
+// withMiddleware оборачивает выполнение события в общие middleware и middleware его триггера
+func (e *eventLoop) withMiddleware(ev event.Interface, handler Handler) Handler {
+	e.mx.RLock()
+	var (
+		global  = e.middleware
+		trigger = e.triggerMiddleware[eventsContainer.TriggerKey(ev.GetNamespace(), ev.GetTriggerName())]
+	)
+	e.mx.RUnlock()
+
+	for i := len(trigger) - 1; i >= 0; i-- {
+		handler = trigger[i](handler)
+	}
+	for i := len(global) - 1; i >= 0; i-- {
+		handler = global[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,202 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+// recordingMiddleware пишет в calls name до и после вызова next
+func recordingMiddleware(mx *sync.Mutex, calls *[]string, name string) Middleware {
+	record := func(call string) {
+		mx.Lock()
+		*calls = append(*calls, call)
+		mx.Unlock()
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, ev event.Interface) event.Result {
+			record(">" + name)
+			result := next(ctx, ev)
+			record("<" + name)
+			return result
+		}
+	}
+}
+
+// mustUseTrigger добавляет middleware триггера, ошибка UseTrigger проваливает тест
+func mustUseTrigger(t *testing.T, e Interface, triggerName string, middleware ...Middleware) {
+	t.Helper()
+	if err := e.UseTrigger(triggerName, middleware...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_eventLoop_Use(t *testing.T) {
+	errDenied := errors.New("denied")
+	deny := func(next Handler) Handler {
+		return func(ctx context.Context, ev event.Interface) event.Result {
+			return event.Result{UUID: ev.GetUUID(), Status: event.StatusFailed, Err: errDenied}
+		}
+	}
+	panicking := func(next Handler) Handler {
+		return func(ctx context.Context, ev event.Interface) event.Result {
+			panic("boom")
+		}
+	}
+	tests := []struct {
+		name string
+		// use добавляет middleware, calls - куда они пишут вызовы
+		use       func(t *testing.T, e Interface, mx *sync.Mutex, calls *[]string)
+		wantCalls []string
+		wantErr   error
+		// wantPanic - результат с *event.PanicError из panicking
+		wantPanic bool
+	}{
+		{
+			name: "Global then trigger, in order",
+			use: func(t *testing.T, e Interface, mx *sync.Mutex, calls *[]string) {
+				mustUseTrigger(t, e, "Trig", recordingMiddleware(mx, calls, "trigger"))
+				e.Use(recordingMiddleware(mx, calls, "a"), recordingMiddleware(mx, calls, "b"))
+				mustUseTrigger(t, e, "Other", recordingMiddleware(mx, calls, "other"))
+			},
+			wantCalls: []string{">a", ">b", ">trigger", "fn", "<trigger", "<b", "<a"},
+		},
+		{
+			name: "Short circuit",
+			use: func(t *testing.T, e Interface, mx *sync.Mutex, calls *[]string) {
+				e.Use(recordingMiddleware(mx, calls, "a"))
+				mustUseTrigger(t, e, "Trig", deny)
+			},
+			wantCalls: []string{">a", "<a"},
+			wantErr:   errDenied,
+		},
+		{
+			name: "Panic",
+			use: func(t *testing.T, e Interface, mx *sync.Mutex, calls *[]string) {
+				e.Use(recordingMiddleware(mx, calls, "a"))
+				mustUseTrigger(t, e, "Trig", panicking)
+			},
+			wantCalls: []string{">a"},
+			wantPanic: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					mx    sync.Mutex
+					calls []string
+					e     = NewEventLoop(WithLogger(newTestLogger()))
+					ev, _ = event.NewEvent(
+						event.Args{
+							TriggerName: "Trig",
+							Fun: func(ctx context.Context) string {
+								mx.Lock()
+								calls = append(calls, "fn")
+								mx.Unlock()
+								return "OK"
+							},
+						},
+					)
+				)
+				tt.use(t, e, &mx, &calls)
+				if err := e.RegisterEvent(context.Background(), ev); err != nil {
+					t.Fatal(err)
+				}
+				result, err := e.Trigger(context.Background(), "Trig")
+				if err != nil {
+					t.Fatal(err)
+				}
+				var panicErr *event.PanicError
+				if tt.wantPanic {
+					if !errors.As(result.Events[0].Err, &panicErr) || panicErr.Value != "boom" ||
+						result.Events[0].Status != event.StatusFailed {
+						t.Errorf("Result = %+v, want failed with panic \"boom\"", result.Events[0])
+					}
+				} else if !errors.Is(result.Events[0].Err, tt.wantErr) {
+					t.Errorf("Result.Err = %v, want %v", result.Events[0].Err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(calls, tt.wantCalls) {
+					t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_Use_Interval(t *testing.T) {
+	var (
+		fake = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+		runs = make(chan string, 10)
+		e    = NewEventLoop(
+			WithLogger(newTestLogger()), WithClock(fake), WithMiddleware(
+				func(next Handler) Handler {
+					return func(ctx context.Context, ev event.Interface) event.Result {
+						runs <- ev.GetUUID()
+						return next(ctx, ev)
+					}
+				},
+			),
+		)
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
+			event.Args{
+				TriggerName:  "Interval",
+				IntervalTime: time.Minute,
+				Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	defer e.RemoveEventByUUIDs(ev.GetUUID())
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Trigger(ctx, "Interval"); err != nil {
+		t.Fatal(err)
+	}
+	fake.BlockUntil(1)
+
+	fake.Advance(time.Minute)
+	if got := <-runs; got != ev.GetUUID() {
+		t.Errorf("middleware got event %v, want %v", got, ev.GetUUID())
+	}
+}
+
+func Test_namespacedLoop_Use(t *testing.T) {
+	var (
+		e         = NewEventLoop(WithLogger(newTestLogger()))
+		ctx       = context.Background()
+		acme, _   = e.Namespace("acme")
+		globex, _ = e.Namespace("globex")
+		mx        sync.Mutex
+		calls     []string
+	)
+	acme.Use(recordingMiddleware(&mx, &calls, "acme"))
+	mustUseTrigger(t, globex, "order", recordingMiddleware(&mx, &calls, "globex"))
+	if err := acme.RegisterEvent(ctx, newNamespacedEvent(t, "acme", "order")); err != nil {
+		t.Fatal(err)
+	}
+	if err := globex.RegisterEvent(ctx, newNamespacedEvent(t, "globex", "order")); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.RegisterEvent(ctx, newNamespacedEvent(t, "", "order")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, loop := range []Interface{e, acme, globex} {
+		if _, err := loop.Trigger(ctx, "order"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{">acme", "<acme", ">globex", "<globex"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
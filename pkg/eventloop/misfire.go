@@ -0,0 +1,69 @@
+package eventloop
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+// maxMissedRuns - сколько пропущенных запусков максимум выполняет MisfireRunAll за раз. После долгого сна машины
+// интервал в миллисекунду накопил бы миллионы запусков
+const maxMissedRuns = 1000
+
+// missedRuns возвращает опоздания запусков, которые нужно выполнить, если запуск, назначенный на scheduled, наступил
+// только в now. next - время запуска по расписанию, следующего за переданным; nil - разовый запуск.
+// Пустой результат - выполнять нечего
+func missedRuns(
+	misfire event.Misfire,
+	scheduled, now time.Time,
+	next func(time.Time) time.Time,
+) []time.Duration {
+	lateBy := now.Sub(scheduled)
+	if lateBy < 0 {
+		lateBy = 0
+	}
+	if lateBy <= misfire.Threshold {
+		return []time.Duration{lateBy}
+	}
+
+	switch misfire.Policy {
+	case event.MisfireSkip:
+		return nil
+	case event.MisfireRunAll:
+		if next == nil {
+			return []time.Duration{lateBy}
+		}
+		var runs []time.Duration
+		for slot := scheduled; !slot.IsZero() && !slot.After(now) && len(runs) < maxMissedRuns; slot = next(slot) {
+			runs = append(runs, now.Sub(slot))
+		}
+		return runs
+	default:
+		return []time.Duration{lateBy}
+	}
+}
+
+// runLate выполняет функцию события, запущенного по расписанию, и отмечает в результате, насколько запуск опоздал
+func (e *eventLoop) runLate(ctx context.Context, ev event.Interface, lateBy time.Duration) event.Result {
+	if lateBy > ev.GetMisfire().Threshold {
+		e.logger.Warnw("Scheduled run is late", "eventId", ev.GetUUID(), "lateBy", lateBy)
+	}
+	result := e.runEventFunction(ctx, ev)
+	result.LateBy = lateBy
+	return result
+}
+
+// missedResult - результат запуска, пропущенного по политике MisfireSkip
+func (e *eventLoop) missedResult(ev event.Interface, lateBy time.Duration) event.Result {
+	now := e.clock.Now()
+	e.logger.Warnw("Scheduled run missed, skipping", "eventId", ev.GetUUID(), "lateBy", lateBy)
+	return event.Result{
+		UUID:     ev.GetUUID(),
+		Priority: ev.GetPriority(),
+		Status:   event.StatusMissed,
+		Start:    now,
+		End:      now,
+		LateBy:   lateBy,
+	}
+}
@@ -0,0 +1,158 @@
+package eventloop
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+)
+
+func Test_missedRuns(t *testing.T) {
+	var (
+		scheduled = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+		everyTen  = func(slot time.Time) time.Time {
+			return slot.Add(10 * time.Second)
+		}
+	)
+	tests := []struct {
+		name    string
+		policy  event.MisfirePolicy
+		lateBy  time.Duration
+		oneShot bool
+		want    []time.Duration
+	}{
+		{
+			name:   "In time",
+			policy: event.MisfireSkip,
+			lateBy: 500 * time.Millisecond,
+			want:   []time.Duration{500 * time.Millisecond},
+		},
+		{
+			name:   "Early",
+			policy: event.MisfireRunOnce,
+			lateBy: -time.Second,
+			want:   []time.Duration{0},
+		},
+		{
+			name:   "Run once",
+			policy: event.MisfireRunOnce,
+			lateBy: 25 * time.Second,
+			want:   []time.Duration{25 * time.Second},
+		},
+		{
+			name:   "Run all",
+			policy: event.MisfireRunAll,
+			lateBy: 25 * time.Second,
+			want:   []time.Duration{25 * time.Second, 15 * time.Second, 5 * time.Second},
+		},
+		{
+			name:    "Run all, one-shot",
+			policy:  event.MisfireRunAll,
+			lateBy:  25 * time.Second,
+			oneShot: true,
+			want:    []time.Duration{25 * time.Second},
+		},
+		{
+			name:   "Skip",
+			policy: event.MisfireSkip,
+			lateBy: 25 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				next := everyTen
+				if tt.oneShot {
+					next = nil
+				}
+				misfire := event.Misfire{Policy: tt.policy, Threshold: time.Second}
+				got := missedRuns(misfire, scheduled, scheduled.Add(tt.lateBy), next)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("missedRuns() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_missedRuns_Limit(t *testing.T) {
+	var (
+		scheduled = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+		misfire   = event.Misfire{Policy: event.MisfireRunAll, Threshold: time.Second}
+		next      = func(slot time.Time) time.Time {
+			return slot.Add(time.Millisecond)
+		}
+	)
+	if got := missedRuns(misfire, scheduled, scheduled.Add(time.Hour), next); len(got) != maxMissedRuns {
+		t.Errorf("missedRuns() returned %v runs, want %v", len(got), maxMissedRuns)
+	}
+}
+
+func Test_eventLoop_Trigger_Misfire(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     event.MisfirePolicy
+		wantStatus event.Status
+		wantCalls  int32
+	}{
+		{
+			name:       "Run once",
+			policy:     event.MisfireRunOnce,
+			wantStatus: event.StatusDone,
+			wantCalls:  1,
+		},
+		{
+			name:       "Skip",
+			policy:     event.MisfireSkip,
+			wantStatus: event.StatusMissed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					calls int32
+					e     = NewEventLoop(WithLogger(newTestLogger()))
+					ctx   = context.Background()
+					// AFTER событие, время которого прошло час назад
+					ev, _ = event.NewEvent(
+						event.Args{
+							TriggerName: "Trig",
+							DateAfter:   after.Args{Date: time.Now().Add(-time.Hour).AddDate(1, 1, 1)},
+							Misfire:     event.Misfire{Policy: tt.policy},
+							Fun: func(ctx context.Context) string {
+								atomic.AddInt32(&calls, 1)
+								return "OK"
+							},
+						},
+					)
+				)
+				if err := e.RegisterEvent(ctx, ev); err != nil {
+					t.Fatal(err)
+				}
+
+				scheduled, err := e.Trigger(ctx, "Trig")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if scheduled.Events[0].Status != event.StatusScheduled {
+					t.Fatalf("Trigger() status = %v, want %v", scheduled.Events[0].Status, event.StatusScheduled)
+				}
+				got := <-scheduled.Events[0].Completion
+				if got.Status != tt.wantStatus {
+					t.Errorf("Trigger() status = %v, want %v", got.Status, tt.wantStatus)
+				}
+				if lateBy := got.LateBy; lateBy < 59*time.Minute {
+					t.Errorf("Trigger() LateBy = %v, want about an hour", lateBy)
+				}
+				if calls := atomic.LoadInt32(&calls); calls != tt.wantCalls {
+					t.Errorf("event function called %v times, want %v", calls, tt.wantCalls)
+				}
+			},
+		)
+	}
+}
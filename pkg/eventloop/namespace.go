@@ -0,0 +1,303 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("namespace quota exceeded")
+	// ErrForeignNamespace - событие или операция из другого пространства имён
+	ErrForeignNamespace = errors.New("event belongs to another namespace")
+)
+
+// Quota - ограничения пространства имён. 0 - без ограничения
+type Quota struct {
+	// MaxEvents - сколько всего событий может быть зарегистрировано в пространстве
+	MaxEvents int
+	// MaxIntervalEvents - сколько из них может быть интервальными
+	MaxIntervalEvents int
+}
+
+func (q Quota) validate() error {
+	if q.MaxEvents < 0 || q.MaxIntervalEvents < 0 {
+		return fmt.Errorf("negative quota %+v", q)
+	}
+	return nil
+}
+
+// ConfigureNamespace задаёт квоты пространства имён. Уже зарегистрированные события квота не удаляет, она проверяется
+// при регистрации новых
+func (e *eventLoop) ConfigureNamespace(namespace string, quota Quota) error {
+	if err := event.ValidateNamespace(namespace); err != nil {
+		return err
+	}
+	if err := quota.validate(); err != nil {
+		return err
+	}
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.quotas == nil {
+		e.quotas = make(map[string]Quota)
+	}
+	e.quotas[namespace] = quota
+	e.logger.Debugw("Namespace configured", "namespace", namespace, "quota", quota)
+	return nil
+}
+
+// checkQuota проверяет, поместится ли событие в квоту своего пространства имён. Вызывающий держит e.mx
+func (e *eventLoop) checkQuota(ev event.Interface) error {
+	namespace := ev.GetNamespace()
+	quota, ok := e.quotas[namespace]
+	if namespace == "" || !ok {
+		return nil
+	}
+	var (
+		events    = e.events.GetEventsByNamespace(namespace)
+		intervals int
+	)
+	if quota.MaxEvents > 0 && len(events) >= quota.MaxEvents {
+		return fmt.Errorf("%w: namespace %q already has %v events", ErrQuotaExceeded, namespace, len(events))
+	}
+	if _, err := ev.Interval(); err != nil || quota.MaxIntervalEvents == 0 {
+		return nil
+	}
+	for _, registered := range events {
+		if _, err := registered.Interval(); err == nil {
+			intervals++
+		}
+	}
+	if intervals >= quota.MaxIntervalEvents {
+		return fmt.Errorf("%w: namespace %q already has %v interval events", ErrQuotaExceeded, namespace, intervals)
+	}
+	return nil
+}
+
+// Namespace возвращает менеджер событий, ограниченный пространством имён (арендатором). Триггеры, их настройки и
+// переключение, список триггеров и операции по UUID видят только события этого пространства, а регистрировать можно
+// только события с event.Args.Namespace == namespace. Общие для всего менеджера вещи - функции менеджера
+// (ToggleEventLoopFuncs), Shutdown и вложенные пространства - из пространства недоступны
+func (e *eventLoop) Namespace(namespace string) (Interface, error) {
+	if err := event.ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	return &namespacedLoop{eventLoop: e, namespace: namespace}, nil
+}
+
+// namespacedLoop - менеджер событий одного пространства имён. Имена триггеров пространства хранятся в контейнере под
+// ключами eventsContainer.TriggerKey, всё, что не переопределено, работает как у eventLoop
+type namespacedLoop struct {
+	*eventLoop
+	namespace string
+}
+
+func (n *namespacedLoop) key(triggerName string) string {
+	return eventsContainer.TriggerKey(n.namespace, triggerName)
+}
+
+func (n *namespacedLoop) keys(triggerNames []string) []string {
+	result := make([]string, 0, len(triggerNames))
+	for _, name := range triggerNames {
+		result = append(result, n.key(name))
+	}
+	return result
+}
+
+// checkEvents проверяет, что все события из этого пространства имён
+func (n *namespacedLoop) checkEvents(events ...event.Interface) error {
+	for _, ev := range events {
+		if ev.GetNamespace() != n.namespace {
+			return fmt.Errorf("%w: event %v is in %q, not in %q", ErrForeignNamespace, ev.GetUUID(),
+				ev.GetNamespace(), n.namespace)
+		}
+	}
+	return nil
+}
+
+// own делит UUID на события этого пространства и остальные, которых для пространства как будто нет
+func (n *namespacedLoop) own(uUIDs []string) (own, missing []string) {
+	events, missing := n.eventsByUUIDs(uUIDs...)
+	for _, ev := range events {
+		if ev.GetNamespace() == n.namespace {
+			own = append(own, ev.GetUUID())
+		} else {
+			missing = append(missing, ev.GetUUID())
+		}
+	}
+	return own, missing
+}
+
+func (n *namespacedLoop) RegisterEvent(ctx context.Context, newEvents ...event.Interface) error {
+	if err := n.checkEvents(newEvents...); err != nil {
+		return err
+	}
+	return n.eventLoop.RegisterEvent(ctx, newEvents...)
+}
+
+func (n *namespacedLoop) Trigger(ctx context.Context, triggerName string) (TriggerResult, error) {
+	return n.TriggerWithPayload(ctx, triggerName, nil)
+}
+
+func (n *namespacedLoop) TriggerWithPayload(
+	ctx context.Context,
+	triggerName string,
+	payload any,
+) (TriggerResult, error) {
+	result, err := n.triggerWithPayload(ctx, n.key(triggerName), payload)
+	result.TriggerName = triggerName
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		rateErr.TriggerName = triggerName
+	}
+	return result, err
+}
+
+// OnError добавляет хуки, которые видят ошибки только событий этого пространства имён
+func (n *namespacedLoop) OnError(hooks ...ErrorHook) {
+	for _, hook := range hooks {
+		hook := hook
+		n.eventLoop.OnError(
+			func(ctx context.Context, result event.Result) {
+				if events, _ := n.eventsByUUIDs(result.UUID); len(events) == 1 &&
+					events[0].GetNamespace() == n.namespace {
+					hook(ctx, result)
+				}
+			},
+		)
+	}
+}
+
+// Use добавляет middleware, через которые проходят только события этого пространства имён
+func (n *namespacedLoop) Use(middleware ...Middleware) {
+	for _, mw := range middleware {
+		mw := mw
+		n.eventLoop.Use(
+			func(next Handler) Handler {
+				wrapped := mw(next)
+				return func(ctx context.Context, ev event.Interface) event.Result {
+					if ev.GetNamespace() != n.namespace {
+						return next(ctx, ev)
+					}
+					return wrapped(ctx, ev)
+				}
+			},
+		)
+	}
+}
+
+func (n *namespacedLoop) UseTrigger(triggerName string, middleware ...Middleware) error {
+	if err := event.ValidateTriggerName(triggerName); err != nil {
+		return err
+	}
+	n.useTrigger(n.key(triggerName), middleware...)
+	return nil
+}
+
+func (n *namespacedLoop) ConfigureTrigger(triggerName string, config TriggerConfig) error {
+	return n.configureTrigger(n.key(triggerName), config)
+}
+
+func (n *namespacedLoop) GetTriggerConfig(triggerName string) TriggerConfig {
+	return n.eventLoop.GetTriggerConfig(n.key(triggerName))
+}
+
+func (n *namespacedLoop) ToggleEventLoopFuncs(...EventFunction) string {
+	return fmt.Sprintf("event loop functions can't be toggled from namespace %q", n.namespace)
+}
+
+func (n *namespacedLoop) ToggleTriggers(triggerNames ...string) string {
+	return n.toggleTriggers(n.namespace, triggerNames...)
+}
+
+func (n *namespacedLoop) RemoveEventByUUIDs(uUIDs ...string) []string {
+	own, missing := n.own(uUIDs)
+	return append(missing, n.eventLoop.RemoveEventByUUIDs(own...)...)
+}
+
+func (n *namespacedLoop) RemoveTriggers(triggers ...string) (result []string) {
+	for _, key := range n.removeTriggers(n.keys(triggers)...) {
+		_, triggerName := eventsContainer.SplitTriggerKey(key)
+		result = append(result, triggerName)
+	}
+	return result
+}
+
+func (n *namespacedLoop) PauseEvent(uUIDs ...string) []string {
+	own, missing := n.own(uUIDs)
+	return append(missing, n.eventLoop.PauseEvent(own...)...)
+}
+
+func (n *namespacedLoop) ResumeEvent(uUIDs ...string) []string {
+	own, missing := n.own(uUIDs)
+	return append(missing, n.eventLoop.ResumeEvent(own...)...)
+}
+
+func (n *namespacedLoop) CancelEvent(uUIDs ...string) []string {
+	own, missing := n.own(uUIDs)
+	return append(missing, n.eventLoop.CancelEvent(own...)...)
+}
+
+func (n *namespacedLoop) StartInterval(uUID string) error {
+	if own, _ := n.own([]string{uUID}); len(own) == 0 {
+		return ErrNoEvent
+	}
+	return n.eventLoop.StartInterval(uUID)
+}
+
+func (n *namespacedLoop) StopInterval(uUID string) error {
+	if own, _ := n.own([]string{uUID}); len(own) == 0 {
+		return ErrNoEvent
+	}
+	return n.eventLoop.StopInterval(uUID)
+}
+
+func (n *namespacedLoop) Subscribe(ctx context.Context, triggers []event.Interface, listeners []event.Interface) error {
+	if err := n.checkEvents(triggers...); err != nil {
+		return err
+	}
+	if err := n.checkEvents(listeners...); err != nil {
+		return err
+	}
+	return n.eventLoop.Subscribe(ctx, triggers, listeners)
+}
+
+func (n *namespacedLoop) Unsubscribe(triggers []string, listeners []string) []string {
+	ownTriggers, missing := n.ownSubscribers(triggers)
+	ownListeners, missingListeners := n.ownSubscribers(listeners)
+	missing = append(missing, missingListeners...)
+	return append(missing, n.eventLoop.Unsubscribe(ownTriggers, ownListeners)...)
+}
+
+// ownSubscribers делит UUID на подписанные события этого пространства и остальные
+func (n *namespacedLoop) ownSubscribers(uUIDs []string) (own, missing []string) {
+	n.mx.RLock()
+	defer n.mx.RUnlock()
+	for _, id := range uUIDs {
+		if ev, ok := n.subscribers[id]; ok && ev.GetNamespace() == n.namespace {
+			own = append(own, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return own, missing
+}
+
+func (n *namespacedLoop) GetAttachedEvents(triggerName string) []event.Interface {
+	return n.eventLoop.GetAttachedEvents(n.key(triggerName))
+}
+
+func (n *namespacedLoop) GetTriggerNames() AllTriggers {
+	return AllTriggers{userTriggers: n.triggerNames(n.namespace), systemTriggers: allSystemTriggers}
+}
+
+func (n *namespacedLoop) Namespace(string) (Interface, error) {
+	return nil, fmt.Errorf("namespace %q can't have nested namespaces", n.namespace)
+}
+
+func (n *namespacedLoop) Shutdown(context.Context) (ShutdownReport, error) {
+	return ShutdownReport{}, fmt.Errorf("namespace %q can't shut down event loop", n.namespace)
+}
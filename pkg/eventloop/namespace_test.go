@@ -0,0 +1,315 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
+	"golang.org/x/exp/slices"
+)
+
+// newNamespacedEvent - событие триггера triggerName в пространстве namespace, функция которого возвращает namespace
+func newNamespacedEvent(t *testing.T, namespace, triggerName string) event.Interface {
+	t.Helper()
+	ev, err := event.NewEvent(
+		event.Args{
+			TriggerName: triggerName,
+			Namespace:   namespace,
+			Fun: func(ctx context.Context) string {
+				return namespace
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func Test_eventLoop_Namespace(t *testing.T) {
+	var (
+		e           = NewEventLoop(WithLogger(newTestLogger()))
+		ctx         = context.Background()
+		acme, _     = e.Namespace("acme")
+		globex, _   = e.Namespace("globex")
+		sharedEv    = newNamespacedEvent(t, "", "order.created")
+		acmeEv      = newNamespacedEvent(t, "acme", "order.created")
+		acmePattern = newNamespacedEvent(t, "acme", "order.*")
+		globexEv    = newNamespacedEvent(t, "globex", "order.created")
+	)
+	if err := e.RegisterEvent(ctx, sharedEv); err != nil {
+		t.Fatal(err)
+	}
+	if err := acme.RegisterEvent(ctx, acmeEv, acmePattern); err != nil {
+		t.Fatal(err)
+	}
+	if err := globex.RegisterEvent(ctx, globexEv); err != nil {
+		t.Fatal(err)
+	}
+	foreign := newNamespacedEvent(t, "acme", "foreign")
+	if err := globex.RegisterEvent(ctx, foreign); !errors.Is(err, ErrForeignNamespace) {
+		t.Errorf("RegisterEvent() of foreign event error = %v, want %v", err, ErrForeignNamespace)
+	}
+
+	// values - значения событий, выполненных вызовом триггера
+	values := func(loop Interface, triggerName string) []string {
+		result, err := loop.Trigger(ctx, triggerName)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		if result.TriggerName != triggerName {
+			t.Errorf("TriggerResult.TriggerName = %v, want %v", result.TriggerName, triggerName)
+		}
+		got := result.Values()
+		slices.Sort(got)
+		return got
+	}
+
+	tests := []struct {
+		name string
+		loop Interface
+		want []string
+	}{
+		{name: "Shared", loop: e, want: []string{""}},
+		{name: "Acme with pattern", loop: acme, want: []string{"acme", "acme"}},
+		{name: "Globex", loop: globex, want: []string{"globex"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := values(tt.loop, "order.created"); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Trigger() ran %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Trigger names", func(t *testing.T) {
+			got := acme.GetTriggerNames().userTriggers
+			slices.Sort(got)
+			if want := []string{"order.*", "order.created"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetTriggerNames() = %v, want %v", got, want)
+			}
+			if got := e.GetTriggerNames().userTriggers; !reflect.DeepEqual(got, []string{"order.created"}) {
+				t.Errorf("shared GetTriggerNames() = %v, want [order.created]", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Operations by UUID", func(t *testing.T) {
+			if missing := globex.PauseEvent(acmeEv.GetUUID()); !reflect.DeepEqual(missing, []string{acmeEv.GetUUID()}) {
+				t.Errorf("PauseEvent() of foreign event = %v, want it missing", missing)
+			}
+			if acmeEv.IsPaused() {
+				t.Error("foreign namespace paused the event")
+			}
+			if err := globex.StartInterval(acmeEv.GetUUID()); !errors.Is(err, ErrNoEvent) {
+				t.Errorf("StartInterval() of foreign event error = %v, want %v", err, ErrNoEvent)
+			}
+		},
+	)
+
+	t.Run(
+		"Toggle and remove", func(t *testing.T) {
+			acme.ToggleTriggers("order.created")
+			if _, err := acme.Trigger(ctx, "order.created"); err == nil {
+				t.Error("Trigger() of disabled trigger error = nil")
+			}
+			if got := values(globex, "order.created"); !reflect.DeepEqual(got, []string{"globex"}) {
+				t.Errorf("globex Trigger() ran %v after acme toggle", got)
+			}
+			acme.ToggleTriggers("order.created")
+
+			if missing := globex.RemoveTriggers("order.created", "order.*"); !reflect.DeepEqual(
+				missing,
+				[]string{"order.*"},
+			) {
+				t.Errorf("RemoveTriggers() = %v, want [order.*]", missing)
+			}
+			if got := values(acme, "order.created"); !reflect.DeepEqual(got, []string{"acme", "acme"}) {
+				t.Errorf("acme Trigger() ran %v after globex removal", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Reserved trigger names", func(t *testing.T) {
+			const key = "$acme.order.created"
+			if _, err := e.Trigger(ctx, key); !errors.Is(err, event.ErrReservedTriggerName) {
+				t.Errorf("Trigger() of namespace key error = %v, want %v", err, event.ErrReservedTriggerName)
+			}
+			if err := e.ConfigureTrigger(key, TriggerConfig{}); !errors.Is(err, event.ErrReservedTriggerName) {
+				t.Errorf("ConfigureTrigger() of namespace key error = %v, want %v", err, event.ErrReservedTriggerName)
+			}
+			used := false
+			err := e.UseTrigger(
+				key, func(next Handler) Handler {
+					used = true
+					return next
+				},
+			)
+			if !errors.Is(err, event.ErrReservedTriggerName) {
+				t.Errorf("UseTrigger() of namespace key error = %v, want %v", err, event.ErrReservedTriggerName)
+			}
+			e.ToggleTriggers(key)
+			if missing := e.RemoveTriggers(key); !reflect.DeepEqual(missing, []string{key}) {
+				t.Errorf("RemoveTriggers() of namespace key = %v, want [%v]", missing, key)
+			}
+			if got := values(acme, "order.created"); !reflect.DeepEqual(got, []string{"acme", "acme"}) {
+				t.Errorf("acme Trigger() ran %v after shared loop used its key", got)
+			}
+			if used {
+				t.Error("UseTrigger() of namespace key added middleware to acme trigger")
+			}
+		},
+	)
+
+	if _, err := acme.Namespace("nested"); err == nil {
+		t.Error("nested Namespace() error = nil")
+	}
+	if _, err := e.Namespace("bad.name"); err == nil {
+		t.Error("Namespace() with dot error = nil")
+	}
+}
+
+func Test_eventLoop_ConfigureNamespace(t *testing.T) {
+	newInterval := func(t *testing.T) event.Interface {
+		ev, err := event.NewEvent(
+			event.Args{
+				IntervalTime: time.Minute,
+				Namespace:    "acme",
+				Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	tests := []struct {
+		name    string
+		quota   Quota
+		events  func(t *testing.T) []event.Interface
+		wantErr []bool
+	}{
+		{
+			name:  "Max events",
+			quota: Quota{MaxEvents: 2},
+			events: func(t *testing.T) []event.Interface {
+				return []event.Interface{
+					newNamespacedEvent(t, "acme", "a"),
+					newInterval(t),
+					newNamespacedEvent(t, "acme", "b"),
+				}
+			},
+			wantErr: []bool{false, false, true},
+		},
+		{
+			name:  "Max interval events",
+			quota: Quota{MaxIntervalEvents: 1},
+			events: func(t *testing.T) []event.Interface {
+				return []event.Interface{newInterval(t), newInterval(t), newNamespacedEvent(t, "acme", "a")}
+			},
+			wantErr: []bool{false, true, false},
+		},
+		{
+			name: "Other namespace is not limited",
+			events: func(t *testing.T) []event.Interface {
+				return []event.Interface{newNamespacedEvent(t, "globex", "a"), newNamespacedEvent(t, "globex", "b")}
+			},
+			quota:   Quota{MaxEvents: 1},
+			wantErr: []bool{false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := NewEventLoop(WithLogger(newTestLogger()))
+				if err := e.ConfigureNamespace("acme", tt.quota); err != nil {
+					t.Fatal(err)
+				}
+				for i, ev := range tt.events(t) {
+					err := e.RegisterEvent(context.Background(), ev)
+					if (err != nil) != tt.wantErr[i] || err != nil && !errors.Is(err, ErrQuotaExceeded) {
+						t.Errorf("event %v: RegisterEvent() error = %v, wantErr %v", i, err, tt.wantErr[i])
+					}
+				}
+			},
+		)
+	}
+
+	e := NewEventLoop(WithLogger(newTestLogger()))
+	if err := e.ConfigureNamespace("acme", Quota{MaxEvents: -1}); err == nil {
+		t.Error("ConfigureNamespace() with negative quota error = nil")
+	}
+}
+
+func Test_eventLoop_Subscribe_Quota(t *testing.T) {
+	newSubscriber := func(t *testing.T, subType subscriber.Type) event.Interface {
+		ev, err := event.NewEvent(
+			event.Args{
+				Namespace:  "acme",
+				Subscriber: subType,
+				Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	tests := []struct {
+		name       string
+		quota      Quota
+		listeners  int
+		wantErr    bool
+		wantEvents int
+	}{
+		{name: "Within quota", quota: Quota{MaxEvents: 3}, listeners: 3, wantEvents: 3},
+		{name: "Quota exceeded", quota: Quota{MaxEvents: 1}, listeners: 6, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := NewEventLoop(WithLogger(newTestLogger()))
+				defer e.Shutdown(context.Background())
+				if err := e.ConfigureNamespace("acme", tt.quota); err != nil {
+					t.Fatal(err)
+				}
+				ns, err := e.Namespace("acme")
+				if err != nil {
+					t.Fatal(err)
+				}
+				triggers := []event.Interface{newSubscriber(t, subscriber.Trigger)}
+				listeners := make([]event.Interface, tt.listeners)
+				for i := range listeners {
+					listeners[i] = newSubscriber(t, subscriber.Listener)
+				}
+
+				err = ns.Subscribe(context.Background(), triggers, listeners)
+				if (err != nil) != tt.wantErr || err != nil && !errors.Is(err, ErrQuotaExceeded) {
+					t.Fatalf("Subscribe() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got := e.(*eventLoop).events.GetEventsByNamespace("acme"); len(got) != tt.wantEvents {
+					t.Errorf("Subscribe() left %v events in namespace, want %v", len(got), tt.wantEvents)
+				}
+				// Повторная подписка тех же слушателей квоту не расходует
+				if err == nil {
+					if err := ns.Subscribe(context.Background(), triggers, listeners); err != nil {
+						t.Errorf("repeated Subscribe() error = %v", err)
+					}
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,111 @@
+package eventloop
+
+import (
+	"fmt"
+
+	"gitlab.com/YSX/eventloop/internal/loggerImplementation"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/workerPool"
+	"gitlab.com/YSX/eventloop/pkg/logger"
+)
+
+// Option - дополнительная настройка менеджера событий для NewEventLoop
+type Option func(e *eventLoop)
+
+// Storage - хранилище событий менеджера. По умолчанию события хранятся в памяти
+type Storage = eventsContainer.Interface
+
+// WithLogger задаёт логгер менеджера событий. Он же передаётся в контекст функций событий (logger.FromContext)
+func WithLogger(l logger.Interface) Option {
+	return func(e *eventLoop) {
+		if l != nil {
+			e.logger = l
+		}
+	}
+}
+
+// WithLogLevel включает встроенный логгер, который пишет в папку logs с уровнем level. Если логгер создать не удалось,
+// логи никуда не пишутся, а ошибка возвращается из InitError
+func WithLogLevel(level string) Option {
+	return func(e *eventLoop) {
+		elLogger, err := loggerImplementation.NewLogger(level, "logs", "")
+		if err != nil {
+			e.initErr = fmt.Errorf("logger init: %w", err)
+			return
+		}
+		e.logger = elLogger
+	}
+}
+
+// WithClock задаёт источник времени для менеджера событий
+func WithClock(c clock.Interface) Option {
+	return func(e *eventLoop) {
+		if c != nil {
+			e.clock = c
+		}
+	}
+}
+
+// WithStorage задаёт хранилище событий вместо хранилища в памяти
+func WithStorage(s Storage) Option {
+	return func(e *eventLoop) {
+		if s != nil {
+			e.events = s
+		}
+	}
+}
+
+// WithErrorHook добавляет хуки ошибок, как OnError
+func WithErrorHook(hooks ...ErrorHook) Option {
+	return func(e *eventLoop) {
+		e.errorHooks = append(e.errorHooks, hooks...)
+	}
+}
+
+// WithMiddleware добавляет middleware для выполнения всех событий, как Use
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(e *eventLoop) {
+		e.middleware = append(e.middleware, middleware...)
+	}
+}
+
+// OverflowPolicy - что делать с выполнением события, если очередь пула воркеров заполнена
+type OverflowPolicy = workerPool.OverflowPolicy
+
+const (
+	// OverflowBlock - ждать места в очереди (по умолчанию)
+	OverflowBlock = workerPool.Block
+	// OverflowDropNewest - не выполнять новое событие, его результат - event.StatusDropped
+	OverflowDropNewest = workerPool.DropNewest
+	// OverflowDropOldest - выкинуть самое старое событие из очереди, его результат - event.StatusDropped
+	OverflowDropOldest = workerPool.DropOldest
+	// OverflowReject - не выполнять новое событие, его результат - event.StatusRejected
+	OverflowReject = workerPool.Reject
+)
+
+// WorkerPoolStats - состояние пула воркеров: размер, занятые воркеры, глубина очереди и счётчики отброшенных событий
+type WorkerPoolStats = workerPool.Stats
+
+var (
+	ErrDropped  = workerPool.ErrDropped
+	ErrRejected = workerPool.ErrRejected
+)
+
+// WithWorkerPool ограничивает число одновременно выполняющихся функций событий. Все выполнения (по триггеру,
+// интервальные, слушатели, системные) идут через пул из workers воркеров с очередью queueSize. Без этой настройки
+// каждая функция выполняется сразу в своей горутине. Функция события, которая сама вызывает Trigger, держит воркер,
+// пока ждёт вложенный триггер - при OverflowBlock и маленьком пуле это может заблокировать цикл
+func WithWorkerPool(workers, queueSize int, overflow OverflowPolicy) Option {
+	return func(e *eventLoop) {
+		e.pool = workerPool.New(workers, queueSize, overflow)
+	}
+}
+
+// WorkerPoolStats возвращает состояние пула воркеров. Если пул не настроен - пустую структуру
+func (e *eventLoop) WorkerPoolStats() WorkerPoolStats {
+	if e.pool == nil {
+		return WorkerPoolStats{}
+	}
+	return e.pool.Stats()
+}
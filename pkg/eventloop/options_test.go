@@ -0,0 +1,333 @@
+package eventloop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+	"gitlab.com/YSX/eventloop/pkg/logger"
+)
+
+func TestNewEventLoop(t *testing.T) {
+	var (
+		lgger   = newTestLogger()
+		clk     = clock.New()
+		storage = eventsContainer.New()
+		hook    = func(ctx context.Context, result event.Result) {}
+	)
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantLogger  logger.Interface
+		wantClock   clock.Interface
+		wantStorage Storage
+		wantHooks   int
+	}{
+		{
+			name:       "Default",
+			wantLogger: logger.NewNop(),
+		},
+		{
+			name: "With options",
+			opts: []Option{
+				WithLogger(lgger), WithClock(clk), WithStorage(storage), WithErrorHook(hook, hook),
+			},
+			wantLogger:  lgger,
+			wantClock:   clk,
+			wantStorage: storage,
+			wantHooks:   2,
+		},
+		{
+			name:       "Nil options are ignored",
+			opts:       []Option{WithLogger(nil), WithClock(nil), WithStorage(nil)},
+			wantLogger: logger.NewNop(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := NewEventLoop(tt.opts...).(*eventLoop)
+				if e.logger != tt.wantLogger {
+					t.Errorf("NewEventLoop() logger = %v, want %v", e.logger, tt.wantLogger)
+				}
+				if e.clock == nil || tt.wantClock != nil && e.clock != tt.wantClock {
+					t.Errorf("NewEventLoop() clock = %v, want %v", e.clock, tt.wantClock)
+				}
+				if e.events == nil || tt.wantStorage != nil && e.events != tt.wantStorage {
+					t.Errorf("NewEventLoop() storage = %v, want %v", e.events, tt.wantStorage)
+				}
+				if len(e.errorHooks) != tt.wantHooks {
+					t.Errorf("NewEventLoop() hooks = %v, want %v", len(e.errorHooks), tt.wantHooks)
+				}
+			},
+		)
+	}
+}
+
+func TestWithLogLevel(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		// logsFile - на месте папки logs лежит файл, логгер не создаётся
+		logsFile bool
+		wantErr  bool
+	}{
+		{
+			name: "Default",
+		},
+		{
+			name:     "Logs path is a file",
+			logsFile: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				dir := t.TempDir()
+				if tt.logsFile {
+					if err := os.WriteFile(filepath.Join(dir, "logs"), nil, 0o600); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := os.Chdir(dir); err != nil {
+					t.Fatal(err)
+				}
+				defer func() {
+					_ = os.Chdir(wd)
+				}()
+
+				e := NewEventLoop(WithLogLevel("error"))
+				if err := e.InitError(); (err != nil) != tt.wantErr {
+					t.Errorf("InitError() = %v, wantErr %v", err, tt.wantErr)
+				}
+				if nop := e.(*eventLoop).logger == logger.NewNop(); nop != tt.wantErr {
+					t.Errorf("NewEventLoop() nop logger = %v, want %v", nop, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestWithWorkerPool(t *testing.T) {
+	tests := []struct {
+		name          string
+		workers       int
+		queueSize     int
+		overflow      OverflowPolicy
+		wantMaxActive int
+		// wantStatus - статус невыполненных событий, если пул должен был часть событий не принять
+		wantStatus event.Status
+	}{
+		{
+			name:          "Block",
+			workers:       2,
+			overflow:      OverflowBlock,
+			wantMaxActive: 2,
+		},
+		{
+			name:          "Reject",
+			workers:       1,
+			queueSize:     1,
+			overflow:      OverflowReject,
+			wantMaxActive: 1,
+			wantStatus:    event.StatusRejected,
+		},
+		{
+			name:          "Drop newest",
+			workers:       1,
+			queueSize:     1,
+			overflow:      OverflowDropNewest,
+			wantMaxActive: 1,
+			wantStatus:    event.StatusDropped,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					mx                sync.Mutex
+					active, maxActive int
+					e                 = NewEventLoop(WithLogger(newTestLogger()), WithWorkerPool(tt.workers, tt.queueSize, tt.overflow))
+					ctx, cancel       = context.WithTimeout(context.Background(), time.Second)
+					fun               = func(ctx context.Context) string {
+						mx.Lock()
+						active++
+						if active > maxActive {
+							maxActive = active
+						}
+						mx.Unlock()
+
+						time.Sleep(20 * time.Millisecond)
+
+						mx.Lock()
+						active--
+						mx.Unlock()
+						return "OK"
+					}
+				)
+				defer cancel()
+
+				for i := 0; i < 4; i++ {
+					ev, _ := event.NewEvent(event.Args{TriggerName: "Trig", Fun: fun})
+					if err := e.RegisterEvent(ctx, ev); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				got, err := e.Trigger(ctx, "Trig")
+				if err != nil {
+					t.Fatal(err)
+				}
+				gotStatus := make(map[event.Status]int)
+				for _, res := range got.Events {
+					gotStatus[res.Status]++
+				}
+				if gotStatus[event.StatusDone]+gotStatus[tt.wantStatus] != len(got.Events) ||
+					gotStatus[event.StatusDone] == 0 ||
+					tt.wantStatus != "" && gotStatus[tt.wantStatus] == 0 {
+					t.Errorf("Trigger() statuses = %v, want %v and %v", gotStatus, event.StatusDone, tt.wantStatus)
+				}
+				if maxActive != tt.wantMaxActive {
+					t.Errorf("Trigger() max parallel events = %v, want %v", maxActive, tt.wantMaxActive)
+				}
+			},
+		)
+	}
+}
+
+func TestWithWorkerPool_Timeout(t *testing.T) {
+	var (
+		mx                sync.Mutex
+		active, maxActive int
+		e                 = NewEventLoop(WithLogger(newTestLogger()), WithWorkerPool(1, 1, OverflowBlock))
+		ctx               = context.Background()
+	)
+	for i := 0; i < 2; i++ {
+		ev, _ := event.NewEvent(
+			event.Args{
+				TriggerName: "Trig",
+				Timeout:     5 * time.Millisecond,
+				// Функция не следит за ctx.Done() и работает дольше таймаута
+				Fun: func(ctx context.Context) string {
+					mx.Lock()
+					active++
+					if active > maxActive {
+						maxActive = active
+					}
+					mx.Unlock()
+
+					time.Sleep(30 * time.Millisecond)
+
+					mx.Lock()
+					active--
+					mx.Unlock()
+					return "LATE"
+				},
+			},
+		)
+		if err := e.RegisterEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := e.Trigger(ctx, "Trig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range got.Events {
+		if res.Status != event.StatusTimeout {
+			t.Errorf("Trigger() status = %v, want %v", res.Status, event.StatusTimeout)
+		}
+	}
+	mx.Lock()
+	defer mx.Unlock()
+	if maxActive != 1 {
+		t.Errorf("Trigger() max parallel functions = %v, want timed out function to hold its worker", maxActive)
+	}
+}
+
+func TestWithWorkerPool_Goroutines(t *testing.T) {
+	const events = 50
+	var (
+		e       = NewEventLoop(WithLogger(newTestLogger()), WithWorkerPool(1, 0, OverflowBlock))
+		ctx     = context.Background()
+		started = make(chan struct{}, events)
+		release = make(chan struct{})
+		done    = make(chan struct{})
+	)
+	for i := 0; i < events; i++ {
+		ev, _ := event.NewEvent(
+			event.Args{
+				TriggerName: "Trig",
+				Fun: func(ctx context.Context) string {
+					started <- struct{}{}
+					<-release
+					return "OK"
+				},
+			},
+		)
+		if err := e.RegisterEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := runtime.NumGoroutine()
+	go func() {
+		defer close(done)
+		if _, err := e.Trigger(ctx, "Trig"); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	// События ждут места в пуле в горутине Trigger, а не каждое в своей
+	if grown := runtime.NumGoroutine() - before; grown > events/5 {
+		t.Errorf("goroutines grown by %v while %v events wait for the worker", grown, events)
+	}
+	close(release)
+	<-done
+}
+
+func Test_eventLoop_WorkerPoolStats(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want WorkerPoolStats
+	}{
+		{
+			name: "No pool",
+			want: WorkerPoolStats{},
+		},
+		{
+			name: "With pool",
+			opts: []Option{WithWorkerPool(3, 10, OverflowDropOldest)},
+			want: WorkerPoolStats{Workers: 3, QueueSize: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := &eventLoop{
+					events: eventsContainer.New(), mx: &sync.RWMutex{}, clock: clock.New(), logger: newTestLogger(),
+				}
+				for _, opt := range tt.opts {
+					opt(e)
+				}
+				if got := e.WorkerPoolStats(); got != tt.want {
+					t.Errorf("WorkerPoolStats() = %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,94 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"golang.org/x/exp/slices"
+)
+
+var ErrEventCancelled = errors.New("event is cancelled")
+
+// PauseEvent ставит события на паузу. Функции событий на паузе не выполняются ни по триггеру, ни по расписанию, а
+// получают event.StatusPaused. Запущенные интервалы и cron продолжают идти по своему расписанию, а AFTER событие,
+// дождавшееся своего времени на паузе, не выполняется. Возвращает UUID из запроса, событий с которыми нет
+func (e *eventLoop) PauseEvent(uUIDs ...string) []string {
+	events, missing := e.eventsByUUIDs(uUIDs...)
+	for _, ev := range events {
+		ev.SetPaused(true)
+	}
+	e.logger.Infow("Events paused", "events", uUIDs, "missing", missing)
+	return missing
+}
+
+// ResumeEvent снимает события с паузы. Пропущенные на паузе запуски не выполняются, следующие идут по расписанию
+func (e *eventLoop) ResumeEvent(uUIDs ...string) []string {
+	events, missing := e.eventsByUUIDs(uUIDs...)
+	for _, ev := range events {
+		ev.SetPaused(false)
+	}
+	e.logger.Infow("Events resumed", "events", uUIDs, "missing", missing)
+	return missing
+}
+
+// CancelEvent отменяет события: удаляет их из менеджера, останавливает запущенные интервалы и cron и прерывает
+// ожидание AFTER событий (ожидание завершается с event.StatusCancelled). Функции, которые уже выполняются, доработают
+// до конца. Как и удаление, отмену можно запретить системным событием BEFORE_REMOVE - тогда возвращаются все UUID из
+// запроса. Иначе возвращает UUID из запроса, событий с которыми нет
+func (e *eventLoop) CancelEvent(uUIDs ...string) []string {
+	events, missing := e.eventsByUUIDs(uUIDs...)
+	lc := removeLifecycle(events)
+	if len(lc.Events) > 0 {
+		if vetoErr := e.lifecycle(context.Background(), lc); vetoErr != nil {
+			return uUIDs
+		}
+	}
+	for _, ev := range events {
+		// Горутины слушателей и ещё не вышедшие циклы расписания могут успеть запустить событие, на паузе они его
+		// уже не выполнят
+		ev.SetPaused(true)
+	}
+	e.removeEvents(uUIDs...)
+
+	for _, ev := range events {
+		e.stopIntervalEvent(ev)
+		if after, err := ev.After(); err == nil {
+			after.Break()
+		}
+	}
+	if len(lc.Events) > 0 {
+		lc.Stage = AFTER_REMOVE
+		_ = e.lifecycle(context.Background(), lc)
+	}
+	e.logger.Infow("Events cancelled", "events", uUIDs, "missing", missing)
+	return missing
+}
+
+// eventsByUUIDs ищет события по UUID. missing - UUID, событий с которыми нет
+func (e *eventLoop) eventsByUUIDs(uUIDs ...string) (events []event.Interface, missing []string) {
+	e.mx.RLock()
+	all := e.events.GetAll()
+	e.mx.RUnlock()
+
+	for _, id := range uUIDs {
+		i := slices.IndexFunc(
+			all, func(ev event.Interface) bool {
+				return ev.GetUUID() == id
+			},
+		)
+		if i == -1 {
+			missing = append(missing, id)
+			continue
+		}
+		events = append(events, all[i])
+	}
+	return events, missing
+}
+
+// pausedResult - результат события, которое не выполнялось, потому что оно на паузе
+func (e *eventLoop) pausedResult(ev event.Interface) event.Result {
+	now := e.clock.Now()
+	e.logger.Debugw("Event is paused, skipping", "eventId", ev.GetUUID())
+	return event.Result{UUID: ev.GetUUID(), Priority: ev.GetPriority(), Status: event.StatusPaused, Start: now, End: now}
+}
@@ -0,0 +1,166 @@
+package eventloop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+	"golang.org/x/exp/slices"
+)
+
+func Test_eventLoop_PauseEvent(t *testing.T) {
+	var (
+		e     = NewEventLoop(WithLogger(newTestLogger()))
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Pause",
+				Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		control     func(uUIDs ...string) []string
+		uUIDs       []string
+		wantMissing []string
+		wantStatus  event.Status
+	}{
+		{
+			name:        "Pause",
+			control:     e.PauseEvent,
+			uUIDs:       []string{ev.GetUUID(), "missing"},
+			wantMissing: []string{"missing"},
+			wantStatus:  event.StatusPaused,
+		},
+		{
+			name:       "Pause twice",
+			control:    e.PauseEvent,
+			uUIDs:      []string{ev.GetUUID()},
+			wantStatus: event.StatusPaused,
+		},
+		{
+			name:       "Resume",
+			control:    e.ResumeEvent,
+			uUIDs:      []string{ev.GetUUID()},
+			wantStatus: event.StatusDone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := tt.control(tt.uUIDs...); !slices.Equal(got, tt.wantMissing) {
+					t.Errorf("missing = %v, want %v", got, tt.wantMissing)
+				}
+				result, err := e.Trigger(ctx, "Pause")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.Events) != 1 || result.Events[0].Status != tt.wantStatus {
+					t.Errorf("Trigger() = %+v, want status %v", result.Events, tt.wantStatus)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_PauseEvent_Interval(t *testing.T) {
+	var (
+		start = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+		fake  = clock.NewFake(start)
+		e     = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		ctx   = context.Background()
+		runs  = make(chan time.Time, 10)
+		ev, _ = event.NewEvent(
+			event.Args{
+				TriggerName:  "Interval",
+				IntervalTime: time.Minute,
+				Fun: func(ctx context.Context) string {
+					runs <- fake.Now()
+					return "OK"
+				},
+			},
+		)
+	)
+	defer e.CancelEvent(ev.GetUUID())
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Trigger(ctx, "Interval"); err != nil {
+		t.Fatal(err)
+	}
+	fake.BlockUntil(1)
+
+	e.PauseEvent(ev.GetUUID())
+	fake.Advance(time.Minute)
+	fake.BlockUntil(1)
+	e.ResumeEvent(ev.GetUUID())
+	fake.Advance(time.Minute)
+
+	// Пауза не сдвигает расписание: после неё интервал срабатывает в свой обычный срок
+	if got, want := <-runs, start.Add(2*time.Minute); !got.Equal(want) {
+		t.Errorf("run after resume at %v, want %v", got, want)
+	}
+	select {
+	case got := <-runs:
+		t.Errorf("unexpected run at %v", got)
+	default:
+	}
+}
+
+func Test_eventLoop_CancelEvent(t *testing.T) {
+	var (
+		fake  = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+		e     = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		ctx   = context.Background()
+		fun   = func(ctx context.Context) string { return "OK" }
+		wait  = after.Args{Date: time.Time{}.AddDate(1, 1, 1).Add(time.Hour), IsRelative: true}
+		ev, _ = event.NewEvent(event.Args{TriggerName: "After", DateAfter: wait, Fun: fun})
+		iv, _ = event.NewEvent(event.Args{TriggerName: "Interval", IntervalTime: time.Minute, Fun: fun})
+	)
+	if err := e.RegisterEvent(ctx, ev, iv); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Trigger(ctx, "Interval"); err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err := e.Trigger(ctx, "After")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Тикер интервала и таймер AFTER события
+	timers := e.(*eventLoop).scheduler.(interface{ Len() int })
+	for deadline := time.Now().Add(time.Second); timers.Len() < 2; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("AFTER event is not waiting")
+		}
+	}
+
+	if got := e.CancelEvent(ev.GetUUID(), iv.GetUUID(), "missing"); !slices.Equal(got, []string{"missing"}) {
+		t.Errorf("CancelEvent() = %v, want [missing]", got)
+	}
+
+	if got := <-scheduled.Events[0].Completion; got.Status != event.StatusCancelled {
+		t.Errorf("Trigger() completion = %+v, want cancelled", got)
+	}
+	interval, _ := iv.Interval()
+	for deadline := time.Now().Add(time.Second); interval.IsRunning(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("interval is still running after CancelEvent()")
+		}
+	}
+	for _, trigger := range []string{"After", "Interval"} {
+		if events := e.GetAttachedEvents(trigger); len(events) != 0 {
+			t.Errorf("GetAttachedEvents(%v) = %v, want no events", trigger, events)
+		}
+	}
+}
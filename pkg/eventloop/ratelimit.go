@@ -0,0 +1,78 @@
+package eventloop
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrRateLimited = errors.New("trigger rate limited")
+
+// RateLimit - жёсткое ограничение вызовов Trigger: не больше Limit за Window, жетоны возвращаются равномерно, как у
+// Throttle. В отличие от Throttle, лишний вызов не подавляется молча, а получает *RateLimitError. Limit == 0 - без
+// ограничения
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (r RateLimit) validate() error {
+	return Throttle(r).validate()
+}
+
+// RateLimitError - вызов Trigger отклонён ограничением частоты. errors.Is(err, ErrRateLimited) для неё true
+type RateLimitError struct {
+	TriggerName string
+	// Global - сработало общее ограничение менеджера (WithRateLimit), а не ограничение триггера
+	Global bool
+	// RetryAfter - через сколько вызов будет разрешён
+	RetryAfter time.Duration
+}
+
+func (re *RateLimitError) Error() string {
+	scope := "trigger"
+	if re.Global {
+		scope = "global"
+	}
+	return fmt.Sprintf("%v rate limit exceeded for %q, retry after %v", scope, re.TriggerName, re.RetryAfter)
+}
+
+func (re *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// WithRateLimit ограничивает вызовы Trigger всех триггеров вместе. Ограничение отдельного триггера задаётся в
+// TriggerConfig.RateLimit, вызов должен пройти оба. Неправильное ограничение (Window <= 0) не применяется
+func WithRateLimit(limit RateLimit) Option {
+	return func(e *eventLoop) {
+		if limit.Limit > 0 && limit.validate() == nil {
+			e.rateLimit = limit
+		}
+	}
+}
+
+// checkRateLimit забирает жетоны вызова Trigger у ограничения триггера и общего ограничения менеджера. Если общее
+// ограничение вызов не пропустило, жетон триггера возвращается: отклонённый вызов не тратит ни одного
+func (e *eventLoop) checkRateLimit(triggerName string) error {
+	now := e.clock.Now()
+	e.mx.RLock()
+	limiter := e.triggerLimiters[triggerName]
+	e.mx.RUnlock()
+
+	var triggerBucket *tokenBucket
+	if limiter != nil && limiter.rateLimit != nil {
+		triggerBucket = limiter.rateLimit
+		if retryAfter := triggerBucket.allow(now); retryAfter > 0 {
+			return &RateLimitError{TriggerName: triggerName, RetryAfter: retryAfter}
+		}
+	}
+	if e.rateLimiter != nil {
+		if retryAfter := e.rateLimiter.allow(now); retryAfter > 0 {
+			if triggerBucket != nil {
+				triggerBucket.refund()
+			}
+			return &RateLimitError{TriggerName: triggerName, Global: true, RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+func TestRateLimitError(t *testing.T) {
+	var err error = &RateLimitError{TriggerName: "Trig", Global: true, RetryAfter: time.Second}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("errors.Is(%v, ErrRateLimited) = false, want true", err)
+	}
+	if got, want := err.Error(), `global rate limit exceeded for "Trig", retry after 1s`; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+}
+
+func Test_tokenBucket_allow(t *testing.T) {
+	var (
+		now = time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+		b   = newTokenBucket(Throttle{Limit: 2, Window: time.Minute}, now)
+	)
+	for i, want := range []time.Duration{0, 0, 30 * time.Second} {
+		if got := b.allow(now); got != want {
+			t.Errorf("call %v: allow() = %v, want %v", i, got, want)
+		}
+	}
+	if got, want := b.allow(now.Add(10*time.Second)), 20*time.Second; got != want {
+		t.Errorf("allow() after 10s = %v, want %v", got, want)
+	}
+}
+
+func Test_eventLoop_Trigger_RateLimit(t *testing.T) {
+	type call struct {
+		trigger string
+		advance time.Duration
+		// wantErr - nil, если вызов разрешён
+		wantErr *RateLimitError
+	}
+	tests := []struct {
+		name   string
+		global RateLimit
+		// limits - ограничения триггеров
+		limits map[string]RateLimit
+		calls  []call
+	}{
+		{
+			name:   "Trigger limit",
+			limits: map[string]RateLimit{"First": {Limit: 1, Window: time.Minute}},
+			calls: []call{
+				{trigger: "First"},
+				{trigger: "First", wantErr: &RateLimitError{TriggerName: "First", RetryAfter: time.Minute}},
+				{trigger: "Second"},
+				{trigger: "First", advance: 20 * time.Second, wantErr: &RateLimitError{
+					TriggerName: "First", RetryAfter: 40 * time.Second,
+				}},
+				{trigger: "First", advance: 40 * time.Second},
+			},
+		},
+		{
+			name:   "Global limit",
+			global: RateLimit{Limit: 2, Window: time.Minute},
+			calls: []call{
+				{trigger: "First"},
+				{trigger: "Second"},
+				{trigger: "Second", wantErr: &RateLimitError{
+					TriggerName: "Second", Global: true, RetryAfter: 30 * time.Second,
+				}},
+				{trigger: "First", advance: 30 * time.Second},
+			},
+		},
+		{
+			name:   "Global limit keeps trigger token",
+			global: RateLimit{Limit: 1, Window: time.Minute},
+			limits: map[string]RateLimit{"First": {Limit: 2, Window: time.Hour}},
+			calls: []call{
+				{trigger: "First"},
+				{trigger: "First", wantErr: &RateLimitError{
+					TriggerName: "First", Global: true, RetryAfter: time.Minute,
+				}},
+				// Второй жетон триггера не потрачен на вызов, отклонённый общим ограничением
+				{trigger: "First", advance: time.Minute},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					fake = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+					e    = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake), WithRateLimit(tt.global))
+					ctx  = context.Background()
+				)
+				for _, trigger := range []string{"First", "Second"} {
+					ev, _ := event.NewEvent(
+						event.Args{
+							TriggerName: trigger,
+							Fun: func(ctx context.Context) string {
+								return "OK"
+							},
+						},
+					)
+					if err := e.RegisterEvent(ctx, ev); err != nil {
+						t.Fatal(err)
+					}
+					if err := e.ConfigureTrigger(trigger, TriggerConfig{RateLimit: tt.limits[trigger]}); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				for i, c := range tt.calls {
+					fake.Advance(c.advance)
+					result, err := e.Trigger(ctx, c.trigger)
+					if c.wantErr == nil {
+						if err != nil || len(result.Events) != 1 {
+							t.Errorf("call %v: Trigger() = %+v, %v, want one event run", i, result, err)
+						}
+						continue
+					}
+					var rateErr *RateLimitError
+					if !errors.As(err, &rateErr) || *rateErr != *c.wantErr {
+						t.Errorf("call %v: Trigger() error = %v, want %v", i, err, c.wantErr)
+					}
+				}
+			},
+		)
+	}
+}
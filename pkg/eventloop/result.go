@@ -0,0 +1,29 @@
+package eventloop
+
+import (
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+// TriggerResult - результат вызова триггера. Events содержит результаты событий триггера в порядке приоритета
+type TriggerResult struct {
+	TriggerName string
+	Events      []event.Result
+	// HaltedBy - UUID события, которое остановило цепочку триггера (event.ErrStop или StopOnError). Оставшиеся события
+	// есть в Events со статусом event.StatusSkipped
+	HaltedBy string
+	// Suppressed - вызов не выполнял события, его подавил debounce или throttle триггера (см. TriggerConfig)
+	Suppressed   bool
+	SuppressedBy SuppressReason
+	// SuppressedCalls - сколько вызовов триггера подавлено с его прошлого выполнения. Заполняется у выполнившего
+	// события вызова
+	SuppressedCalls int
+}
+
+// Values возвращает значения, которые вернули функции событий, в порядке приоритета
+func (tr TriggerResult) Values() []string {
+	result := make([]string, 0, len(tr.Events))
+	for _, v := range tr.Events {
+		result = append(result, v.Value)
+	}
+	return result
+}
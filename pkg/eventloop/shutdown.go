@@ -0,0 +1,164 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"golang.org/x/exp/maps"
+)
+
+var ErrShutdown = errors.New("event loop is shut down")
+
+// ShutdownReport - что было остановлено при Shutdown. Все срезы содержат UUID событий
+type ShutdownReport struct {
+	// StoppedIntervals - запущенные интервальные события
+	StoppedIntervals []string
+	// StoppedCrons - запущенные cron события
+	StoppedCrons []string
+	// StoppedSubscribers - события-слушатели, горутины которых ждали триггеров
+	StoppedSubscribers []string
+	// CancelledWaits - события с задержкой (AFTER), которые ждали своего времени и уже не выполнятся
+	CancelledWaits []string
+	// Aborted - функции событий, которые не успели выполниться до конца контекста Shutdown
+	Aborted []string
+}
+
+// Shutdown останавливает менеджер событий: RegisterEvent, Subscribe и Trigger начинают возвращать ErrShutdown,
+// интервальные и cron события и горутины слушателей останавливаются, ожидание AFTER событий прерывается. Затем
+// Shutdown ждёт выполняющиеся функции событий, пока не закончится ctx. Если ждать пришлось дольше - возвращает ошибку
+// контекста, а невыполнившиеся события перечислены в ShutdownReport.Aborted
+func (e *eventLoop) Shutdown(ctx context.Context) (report ShutdownReport, err error) {
+	e.mx.Lock()
+	if e.isShutdown() {
+		e.mx.Unlock()
+		return report, ErrShutdown
+	}
+	close(e.done)
+	events := e.events.GetAll()
+	e.mx.Unlock()
+
+	e.executions.close()
+	e.logger.Infow("Event loop shutting down")
+
+	for _, ev := range events {
+		if interval, intervalErr := ev.Interval(); intervalErr == nil && interval.IsRunning() {
+			report.StoppedIntervals = append(report.StoppedIntervals, ev.GetUUID())
+		}
+		if cronComponent, cronErr := ev.Cron(); cronErr == nil && cronComponent.IsRunning() {
+			report.StoppedCrons = append(report.StoppedCrons, ev.GetUUID())
+		}
+		if sub, subErr := ev.Subscriber(); subErr == nil && sub.IsRunning() {
+			report.StoppedSubscribers = append(report.StoppedSubscribers, ev.GetUUID())
+		}
+		if after, afterErr := ev.After(); afterErr == nil && after.Break() {
+			report.CancelledWaits = append(report.CancelledWaits, ev.GetUUID())
+		}
+	}
+
+	e.stopIntervals()
+	e.stopCrons()
+
+	report.Aborted = e.executions.wait(ctx)
+	if len(report.Aborted) > 0 {
+		err = ctx.Err()
+	}
+
+	if e.pool != nil {
+		// Зависшие функции держат воркеры, Close не должен держать Shutdown дольше контекста
+		if err != nil {
+			go e.pool.Close()
+		} else {
+			e.pool.Close()
+		}
+	}
+
+	e.logger.Infow(
+		"Event loop stopped",
+		"intervals", report.StoppedIntervals,
+		"crons", report.StoppedCrons,
+		"subscribers", report.StoppedSubscribers,
+		"waits", report.CancelledWaits,
+		"aborted", report.Aborted,
+	)
+	return report, err
+}
+
+func (e *eventLoop) isShutdown() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// loopContext возвращает контекст, который закончится вместе с ctx или при Shutdown менеджера событий
+func (e *eventLoop) loopContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	loopCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-e.done:
+			cancel()
+		case <-loopCtx.Done():
+		}
+	}()
+	return loopCtx, cancel
+}
+
+// executions - выполняющиеся сейчас функции событий, чтобы Shutdown мог их дождаться
+type executions struct {
+	mx      sync.Mutex
+	closed  bool
+	running map[string]int
+	wg      sync.WaitGroup
+}
+
+// begin отмечает начало выполнения функции события. false - менеджер остановлен, выполнять нельзя
+func (ex *executions) begin(ev event.Interface) bool {
+	ex.mx.Lock()
+	defer ex.mx.Unlock()
+	if ex.closed {
+		return false
+	}
+	if ex.running == nil {
+		ex.running = make(map[string]int)
+	}
+	ex.running[ev.GetUUID()]++
+	ex.wg.Add(1)
+	return true
+}
+
+func (ex *executions) end(ev event.Interface) {
+	ex.mx.Lock()
+	defer ex.mx.Unlock()
+	if ex.running[ev.GetUUID()]--; ex.running[ev.GetUUID()] <= 0 {
+		delete(ex.running, ev.GetUUID())
+	}
+	ex.wg.Done()
+}
+
+func (ex *executions) close() {
+	ex.mx.Lock()
+	defer ex.mx.Unlock()
+	ex.closed = true
+}
+
+// wait ждёт окончания всех выполнений или конца ctx. Возвращает UUID событий, которые так и не закончились
+func (ex *executions) wait(ctx context.Context) []string {
+	done := make(chan struct{})
+	go func() {
+		ex.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		ex.mx.Lock()
+		defer ex.mx.Unlock()
+		return maps.Keys(ex.running)
+	}
+}
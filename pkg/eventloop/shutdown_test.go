@@ -0,0 +1,168 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/after"
+)
+
+func Test_eventLoop_Shutdown(t *testing.T) {
+	tests := []struct {
+		name string
+		// fun - функция события по триггеру, Trigger вызывается перед Shutdown
+		fun         event.Func
+		timeout     time.Duration
+		wantAborted bool
+		wantErr     error
+	}{
+		{
+			name: "In-flight function finished",
+			fun: func(ctx context.Context) string {
+				time.Sleep(20 * time.Millisecond)
+				return "OK"
+			},
+			timeout: time.Second,
+		},
+		{
+			name: "In-flight function aborted",
+			fun: func(ctx context.Context) string {
+				time.Sleep(200 * time.Millisecond)
+				return "OK"
+			},
+			timeout:     20 * time.Millisecond,
+			wantAborted: true,
+			wantErr:     context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					e       = NewEventLoop(WithLogger(newTestLogger()))
+					ev, _   = event.NewEvent(event.Args{TriggerName: "Trig", Fun: tt.fun})
+					started = make(chan struct{})
+				)
+				if err := e.RegisterEvent(context.Background(), ev); err != nil {
+					t.Fatal(err)
+				}
+				go func() {
+					close(started)
+					_, _ = e.Trigger(context.Background(), "Trig")
+				}()
+				<-started
+				time.Sleep(5 * time.Millisecond)
+
+				ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+				defer cancel()
+				report, err := e.Shutdown(ctx)
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Shutdown() error = %v, want %v", err, tt.wantErr)
+				}
+				var wantAborted []string
+				if tt.wantAborted {
+					wantAborted = []string{ev.GetUUID()}
+				}
+				if !reflect.DeepEqual(report.Aborted, wantAborted) {
+					t.Errorf("Shutdown() aborted = %v, want %v", report.Aborted, wantAborted)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_Shutdown_RejectsCalls(t *testing.T) {
+	var (
+		e     = NewEventLoop(WithLogger(newTestLogger()))
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig", Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	if _, err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if err := e.RegisterEvent(ctx, ev); !errors.Is(err, ErrShutdown) {
+		t.Errorf("RegisterEvent() error = %v, want %v", err, ErrShutdown)
+	}
+	if _, err := e.Trigger(ctx, "Trig"); !errors.Is(err, ErrShutdown) {
+		t.Errorf("Trigger() error = %v, want %v", err, ErrShutdown)
+	}
+	if err := e.Subscribe(ctx, nil, nil); !errors.Is(err, ErrShutdown) {
+		t.Errorf("Subscribe() error = %v, want %v", err, ErrShutdown)
+	}
+	if _, err := e.Shutdown(ctx); !errors.Is(err, ErrShutdown) {
+		t.Errorf("second Shutdown() error = %v, want %v", err, ErrShutdown)
+	}
+}
+
+func Test_eventLoop_Shutdown_StopsBackground(t *testing.T) {
+	var (
+		e                 = NewEventLoop(WithLogger(newTestLogger()))
+		ctx               = context.Background()
+		ticks, afterCalls int32
+		intervalEv, _     = event.NewEvent(
+			event.Args{
+				TriggerName: "Interval", IntervalTime: time.Millisecond, Fun: func(ctx context.Context) string {
+					atomic.AddInt32(&ticks, 1)
+					return "OK"
+				},
+			},
+		)
+		afterEv, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Trig",
+				DateAfter:   after.Args{Date: time.Time{}.AddDate(1, 1, 1).Add(time.Hour), IsRelative: true},
+				Fun: func(ctx context.Context) string {
+					atomic.AddInt32(&afterCalls, 1)
+					return "OK"
+				},
+			},
+		)
+	)
+	if err := e.RegisterEvent(ctx, intervalEv, afterEv); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Trigger(ctx, "Interval"); err != nil {
+		t.Fatal(err)
+	}
+	scheduled, err := e.Trigger(ctx, "Trig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	report, err := e.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if want := []string{intervalEv.GetUUID()}; !reflect.DeepEqual(report.StoppedIntervals, want) {
+		t.Errorf("Shutdown() stopped intervals = %v, want %v", report.StoppedIntervals, want)
+	}
+	if want := []string{afterEv.GetUUID()}; !reflect.DeepEqual(report.CancelledWaits, want) {
+		t.Errorf("Shutdown() cancelled waits = %v, want %v", report.CancelledWaits, want)
+	}
+
+	if got := <-scheduled.Events[0].Completion; got.Status != event.StatusCancelled {
+		t.Errorf("Trigger() completion after Shutdown() = %+v, want %v", got, event.StatusCancelled)
+	}
+	if calls := atomic.LoadInt32(&afterCalls); calls != 0 {
+		t.Errorf("AFTER event function called %v times, want 0", calls)
+	}
+
+	stopped := atomic.LoadInt32(&ticks)
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(&ticks); got != stopped {
+		t.Errorf("interval ticked %v times after Shutdown()", got-stopped)
+	}
+}
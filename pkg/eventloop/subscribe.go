@@ -0,0 +1,87 @@
+package eventloop
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
+)
+
+// ErrNotSubscriber - событие создано без event.Args.Subscriber нужного типа, подписать его нельзя
+var ErrNotSubscriber = errors.New("event is not a subscriber event")
+
+// checkSubscribers проверяет, что у всех событий есть компонент подписки типа subType
+func checkSubscribers(subType subscriber.Type, events []event.Interface) error {
+	for _, ev := range events {
+		if sub, err := ev.Subscriber(); err != nil || sub.GetType() != subType {
+			return fmt.Errorf("%w: event %v must be %v", ErrNotSubscriber, ev.GetUUID(), subType)
+		}
+	}
+	return nil
+}
+
+func (e *eventLoop) rememberSubscribers(triggers, listeners []event.Interface) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.subscribers == nil {
+		e.subscribers = make(map[string]event.Interface)
+	}
+	for _, ev := range append(append([]event.Interface{}, triggers...), listeners...) {
+		e.subscribers[ev.GetUUID()] = ev
+	}
+}
+
+// Unsubscribe убирает связи слушателей listeners с триггерами triggers (UUID событий из Subscribe), остальные связи
+// остаются. Горутины событий, у которых не осталось связей, останавливаются. Возвращает UUID из запроса, которые не
+// подписаны как триггер или слушатель соответственно
+func (e *eventLoop) Unsubscribe(triggers []string, listeners []string) []string {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	triggerSubs, missing := e.subscribersByUUIDs(subscriber.Trigger, triggers)
+	listenerSubs, missingListeners := e.subscribersByUUIDs(subscriber.Listener, listeners)
+	missing = append(missing, missingListeners...)
+
+	for listenerUUID, listenerSub := range listenerSubs {
+		for triggerUUID, triggerSub := range triggerSubs {
+			unlinked := listenerSub.RemoveChannel(triggerUUID)
+			unlinked = triggerSub.RemoveChannel(listenerUUID) || unlinked
+			if unlinked {
+				e.logger.Infow("Event unsubscribed", "trigger", triggerUUID, "listener", listenerUUID)
+			}
+		}
+	}
+	// Событие без связей больше не подписано, его горутина остановится сама
+	for _, subs := range []map[string]subscriber.Interface{triggerSubs, listenerSubs} {
+		for id, sub := range subs {
+			if len(sub.Links()) == 0 {
+				delete(e.subscribers, id)
+			}
+		}
+	}
+	return missing
+}
+
+// subscribersByUUIDs возвращает компоненты подписки событий типа subType. Вызывающий держит e.mx
+func (e *eventLoop) subscribersByUUIDs(
+	subType subscriber.Type,
+	uUIDs []string,
+) (result map[string]subscriber.Interface, missing []string) {
+	result = make(map[string]subscriber.Interface, len(uUIDs))
+	missing = make([]string, 0)
+	for _, id := range uUIDs {
+		ev, ok := e.subscribers[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		sub, _ := ev.Subscriber()
+		if sub.GetType() != subType {
+			missing = append(missing, id)
+			continue
+		}
+		result[id] = sub
+	}
+	return result, missing
+}
@@ -0,0 +1,167 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event/subscriber"
+)
+
+func newSubscriberEvent(
+	t *testing.T,
+	subType subscriber.Type,
+	triggerName string,
+	fired chan<- string,
+) event.Interface {
+	t.Helper()
+	ev, err := event.NewEvent(
+		event.Args{
+			TriggerName: triggerName,
+			Subscriber:  subType,
+			Fun: func(ctx context.Context) string {
+				if fired != nil {
+					fired <- "fired"
+				}
+				return ""
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+// waitStopped ждёт, пока горутина события подписки остановится
+func waitStopped(t *testing.T, ev event.Interface) {
+	t.Helper()
+	sub, _ := ev.Subscriber()
+	for deadline := time.Now().Add(time.Second); sub.IsRunning(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("runner of %v is still running", ev.GetUUID())
+		}
+	}
+}
+
+func Test_eventLoop_Unsubscribe(t *testing.T) {
+	var (
+		e        = NewEventLoop(WithLogger(newTestLogger()))
+		ctx      = context.Background()
+		fired    = make(chan string, 10)
+		trigger1 = newSubscriberEvent(t, subscriber.Trigger, "T1", nil)
+		trigger2 = newSubscriberEvent(t, subscriber.Trigger, "T2", nil)
+		listener = newSubscriberEvent(t, subscriber.Listener, "", fired)
+	)
+	if err := e.RegisterEvent(ctx, trigger1, trigger2); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Subscribe(ctx, []event.Interface{trigger1, trigger2}, []event.Interface{listener}); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := e.Unsubscribe(
+		[]string{trigger2.GetUUID(), listener.GetUUID(), "unknown"},
+		[]string{listener.GetUUID()},
+	)
+	if want := []string{listener.GetUUID(), "unknown"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("Unsubscribe() = %v, want %v", missing, want)
+	}
+	waitStopped(t, trigger2)
+
+	// Слушатель больше не ждёт trigger2 и срабатывает от одного trigger1
+	if _, err := e.Trigger(ctx, "T1"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("listener did not fire after unsubscribing one of its triggers")
+	}
+	if _, err := e.Trigger(ctx, "T2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if missing = e.Unsubscribe([]string{trigger1.GetUUID()}, []string{listener.GetUUID()}); len(missing) != 0 {
+		t.Errorf("Unsubscribe() = %v, want none", missing)
+	}
+	waitStopped(t, trigger1)
+	waitStopped(t, listener)
+	// Горутина триггера остановлена, Trigger не ждёт её
+	if _, err := e.Trigger(ctx, "T1"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-fired:
+		t.Error("unsubscribed listener fired")
+	default:
+	}
+	if missing = e.Unsubscribe([]string{trigger1.GetUUID()}, nil); len(missing) != 1 {
+		t.Errorf("Unsubscribe() of unlinked trigger = %v, want it missing", missing)
+	}
+}
+
+func Test_eventLoop_Subscribe_ReleasesContext(t *testing.T) {
+	var (
+		e        = NewEventLoop(WithLogger(newTestLogger()))
+		ctx      = context.Background()
+		trigger  = newSubscriberEvent(t, subscriber.Trigger, "T", nil)
+		listener = newSubscriberEvent(t, subscriber.Listener, "", nil)
+	)
+	if err := e.RegisterEvent(ctx, trigger); err != nil {
+		t.Fatal(err)
+	}
+	before := runtime.NumGoroutine()
+	if err := e.Subscribe(ctx, []event.Interface{trigger}, []event.Interface{listener}); err != nil {
+		t.Fatal(err)
+	}
+	e.Unsubscribe([]string{trigger.GetUUID()}, []string{listener.GetUUID()})
+	waitStopped(t, trigger)
+	waitStopped(t, listener)
+
+	// Вместе с горутинами слушателя и триггера завершается и горутина контекста подписки
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %v after Unsubscribe, want %v", runtime.NumGoroutine(), before)
+		}
+	}
+}
+
+func Test_eventLoop_Subscribe_NotSubscriber(t *testing.T) {
+	var (
+		e        = NewEventLoop(WithLogger(newTestLogger()))
+		plain, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "T",
+				Fun: func(ctx context.Context) string {
+					return ""
+				},
+			},
+		)
+		trigger  = newSubscriberEvent(t, subscriber.Trigger, "T", nil)
+		listener = newSubscriberEvent(t, subscriber.Listener, "", nil)
+	)
+	tests := []struct {
+		name      string
+		triggers  []event.Interface
+		listeners []event.Interface
+	}{
+		{name: "Plain trigger", triggers: []event.Interface{plain}, listeners: []event.Interface{listener}},
+		{name: "Plain listener", triggers: []event.Interface{trigger}, listeners: []event.Interface{plain}},
+		{name: "Swapped", triggers: []event.Interface{listener}, listeners: []event.Interface{trigger}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := e.Subscribe(context.Background(), tt.triggers, tt.listeners)
+				if !errors.Is(err, ErrNotSubscriber) {
+					t.Errorf("Subscribe() error = %v, want %v", err, ErrNotSubscriber)
+				}
+			},
+		)
+	}
+}
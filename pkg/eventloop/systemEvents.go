@@ -20,6 +20,12 @@ const (
 	AFTER_TRIGGER  eventLoopSystemTrigger = "@AFTER_TRIGGER"
 	BEFORE_CREATE  eventLoopSystemTrigger = "@BEFORE_CREATE"
 	AFTER_CREATE   eventLoopSystemTrigger = "@AFTER_CREATE"
+	BEFORE_REMOVE  eventLoopSystemTrigger = "@BEFORE_REMOVE"
+	AFTER_REMOVE   eventLoopSystemTrigger = "@AFTER_REMOVE"
+	// ON_ERROR - выполнение любого события завершилось ошибкой, Lifecycle.Result - его результат
+	ON_ERROR       eventLoopSystemTrigger = "@ON_ERROR"
+	INTERVAL_START eventLoopSystemTrigger = "@INTERVAL_START"
+	INTERVAL_STOP  eventLoopSystemTrigger = "@INTERVAL_STOP"
 )
 
 const (
@@ -32,6 +38,11 @@ var allSystemTriggers = []eventLoopSystemTrigger{INTERVALED,
 	AFTER_TRIGGER,
 	BEFORE_CREATE,
 	AFTER_CREATE,
+	BEFORE_REMOVE,
+	AFTER_REMOVE,
+	ON_ERROR,
+	INTERVAL_START,
+	INTERVAL_STOP,
 	AFTER}
 
 var restrictedTriggers = []eventLoopSystemTrigger{
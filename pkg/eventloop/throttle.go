@@ -0,0 +1,89 @@
+package eventloop
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Throttle - события триггера выполняются не больше Limit раз за Window. Ограничение работает как token bucket: в
+// корзине до Limit жетонов, каждое выполнение забирает один, а жетоны возвращаются равномерно, Limit за Window.
+// Вызовы без жетона подавляются. Limit == 0 - throttle выключен
+type Throttle struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (t Throttle) validate() error {
+	switch {
+	case t.Limit < 0:
+		return fmt.Errorf("negative throttle limit %v", t.Limit)
+	case t.Limit > 0 && t.Window <= 0:
+		return fmt.Errorf("throttle window must be positive, got %v", t.Window)
+	}
+	return nil
+}
+
+// tokenBucket - корзина жетонов одного триггера
+type tokenBucket struct {
+	config Throttle
+
+	mx     sync.Mutex
+	tokens float64
+	// last - когда жетоны пересчитывались последний раз
+	last time.Time
+	// suppressed - сколько вызовов подавлено с последнего выполнения
+	suppressed int
+}
+
+func newTokenBucket(config Throttle, now time.Time) *tokenBucket {
+	return &tokenBucket{config: config, tokens: float64(config.Limit), last: now}
+}
+
+// take забирает жетон и возвращает, сколько вызовов было подавлено до этого. ok == false - жетонов нет, вызов нужно
+// подавить
+func (b *tokenBucket) take(now time.Time) (suppressedCalls int, ok bool) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.refill(now)
+	if b.tokens < 1 {
+		b.suppressed++
+		return 0, false
+	}
+	b.tokens--
+	suppressedCalls, b.suppressed = b.suppressed, 0
+	return suppressedCalls, true
+}
+
+// allow забирает жетон. Если жетонов нет, возвращает, через сколько вернётся следующий
+func (b *tokenBucket) allow(now time.Time) (retryAfter time.Duration) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.refill(now)
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) * float64(b.config.Window) / float64(b.config.Limit))
+	}
+	b.tokens--
+	return 0
+}
+
+// refund возвращает жетон, забранный allow, если вызов всё же не прошёл
+func (b *tokenBucket) refund() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.tokens++
+	if limit := float64(b.config.Limit); b.tokens > limit {
+		b.tokens = limit
+	}
+}
+
+// refill возвращает в корзину жетоны, накопившиеся с прошлого пересчёта. Вызывается под b.mx
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(b.config.Limit) * float64(elapsed) / float64(b.config.Window)
+		if limit := float64(b.config.Limit); b.tokens > limit {
+			b.tokens = limit
+		}
+		b.last = now
+	}
+}
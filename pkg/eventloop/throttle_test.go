@@ -0,0 +1,164 @@
+package eventloop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+func TestThrottle_validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		throttle Throttle
+		wantErr  bool
+	}{
+		{
+			name: "Off",
+		},
+		{
+			name:     "Limit per window",
+			throttle: Throttle{Limit: 2, Window: time.Minute},
+		},
+		{
+			name:     "Negative limit",
+			throttle: Throttle{Limit: -1, Window: time.Minute},
+			wantErr:  true,
+		},
+		{
+			name:     "No window",
+			throttle: Throttle{Limit: 2},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if err := tt.throttle.validate(); (err != nil) != tt.wantErr {
+					t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func Test_tokenBucket_take(t *testing.T) {
+	type call struct {
+		after          time.Duration
+		wantOk         bool
+		wantSuppressed int
+	}
+	tests := []struct {
+		name  string
+		calls []call
+	}{
+		{
+			name: "Burst up to limit",
+			calls: []call{
+				{wantOk: true},
+				{wantOk: true},
+				{wantOk: false},
+				{wantOk: false},
+			},
+		},
+		{
+			name: "Token returns after part of window",
+			calls: []call{
+				{wantOk: true},
+				{wantOk: true},
+				{wantOk: false},
+				{after: 30 * time.Second, wantOk: true, wantSuppressed: 1},
+				{wantOk: false},
+			},
+		},
+		{
+			name: "Bucket does not overfill",
+			calls: []call{
+				{after: time.Hour, wantOk: true},
+				{wantOk: true},
+				{wantOk: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				now := time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+				b := newTokenBucket(Throttle{Limit: 2, Window: time.Minute}, now)
+				for i, c := range tt.calls {
+					now = now.Add(c.after)
+					if suppressed, ok := b.take(now); ok != c.wantOk || suppressed != c.wantSuppressed {
+						t.Errorf("call %v: take() = %v, %v, want %v, %v", i, suppressed, ok, c.wantSuppressed, c.wantOk)
+					}
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_Trigger_Throttle(t *testing.T) {
+	var (
+		fake  = clock.NewFake(time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC))
+		e     = NewEventLoop(WithLogger(newTestLogger()), WithClock(fake))
+		ctx   = context.Background()
+		ev, _ = event.NewEvent(
+			event.Args{
+				TriggerName: "Throttled",
+				Fun: func(ctx context.Context) string {
+					return "OK"
+				},
+			},
+		)
+	)
+	if err := e.RegisterEvent(ctx, ev); err != nil {
+		t.Fatal(err)
+	}
+	config := TriggerConfig{Throttle: Throttle{Limit: 1, Window: time.Minute}}
+	if err := e.ConfigureTrigger("Throttled", config); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    TriggerResult
+	}{
+		{
+			name: "First call",
+			want: TriggerResult{TriggerName: "Throttled"},
+		},
+		{
+			name: "No token",
+			want: TriggerResult{TriggerName: "Throttled", Suppressed: true, SuppressedBy: SuppressedByThrottle},
+		},
+		{
+			name: "Still no token",
+			want: TriggerResult{TriggerName: "Throttled", Suppressed: true, SuppressedBy: SuppressedByThrottle},
+		},
+		{
+			name:    "Token returned",
+			advance: time.Minute,
+			want:    TriggerResult{TriggerName: "Throttled", SuppressedCalls: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				fake.Advance(tt.advance)
+				got, err := e.Trigger(ctx, "Throttled")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Suppressed != tt.want.Suppressed || got.SuppressedBy != tt.want.SuppressedBy ||
+					got.SuppressedCalls != tt.want.SuppressedCalls {
+					t.Errorf("Trigger() = %+v, want %+v", got, tt.want)
+				}
+				if !tt.want.Suppressed && len(got.Events) != 1 {
+					t.Errorf("Trigger() ran %v events, want 1", len(got.Events))
+				}
+			},
+		)
+	}
+}
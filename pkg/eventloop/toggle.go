@@ -3,7 +3,9 @@ package eventloop
 import (
 	"fmt"
 
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
 	"gitlab.com/YSX/eventloop/pkg/eventloop/internal"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
 	"gitlab.com/YSX/eventloop/pkg/logger"
 	"golang.org/x/exp/slices"
 )
@@ -36,20 +38,31 @@ func (e *eventLoop) ToggleEventLoopFuncs(eventFuncs ...EventFunction) string {
 	return toggle(&e.disabled, e.logger, eventFuncs...)
 }
 
-func (e *eventLoop) ToggleTriggers(triggerNames ...string) (result string) {
+func (e *eventLoop) ToggleTriggers(triggerNames ...string) string {
+	return e.toggleTriggers("", triggerNames...)
+}
+
+// toggleTriggers переключает триггеры пространства имён, "" - общего
+func (e *eventLoop) toggleTriggers(namespace string, triggerNames ...string) (result string) {
 	for _, name := range triggerNames {
+		key := eventsContainer.TriggerKey(namespace, name)
 		if result != "" {
 			result += " | "
 		}
+		if err := event.ValidateTriggerName(name); err != nil {
+			result += fmt.Sprintf("Can't toggle %v: %v", name, err)
+			e.logger.Warn(result)
+			continue
+		}
 		// Включение
-		if !e.events.IsTriggerEnabled(name) {
+		if !e.events.IsTriggerEnabled(key) {
 			result += fmt.Sprintf("Enabling %v", name)
 			e.logger.Info(result)
-			e.events.ToggleTrigger(name, true)
+			e.events.ToggleTrigger(key, true)
 		} else { // Выключение
 			result += fmt.Sprintf("Disabling %v", name)
 			e.logger.Info(result)
-			e.events.ToggleTrigger(name, false)
+			e.events.ToggleTrigger(key, false)
 		}
 	}
 	return
@@ -0,0 +1,226 @@
+package eventloop
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+)
+
+// ExecutionMode - как выполняются события одного триггера
+type ExecutionMode string
+
+const (
+	// Parallel - все события триггера выполняются одновременно (по умолчанию)
+	Parallel ExecutionMode = "PARALLEL"
+	// Sequential - события выполняются строго по приоритету, каждое следующее ждёт окончания предыдущего
+	Sequential ExecutionMode = "SEQUENTIAL"
+	// PriorityTiers - события с одинаковым приоритетом выполняются одновременно, а группы приоритетов - по очереди
+	PriorityTiers ExecutionMode = "PRIORITY_TIERS"
+)
+
+// TriggerConfig - настройки выполнения событий триггера
+type TriggerConfig struct {
+	Mode ExecutionMode
+	// StopOnError - ошибка любого события останавливает выполнение оставшихся событий, как и event.ErrStop
+	StopOnError bool
+	// Debounce и Throttle подавляют частые вызовы триггера. Подавленный вызов не выполняет события и возвращает
+	// TriggerResult.Suppressed, а их число попадает в TriggerResult.SuppressedCalls следующего выполнения
+	Debounce Debounce
+	Throttle Throttle
+	// RateLimit - жёсткое ограничение вызовов триггера, лишний вызов получает *RateLimitError
+	RateLimit RateLimit
+}
+
+// triggerLimiter - состояние debounce, throttle и ограничения частоты одного триггера. nil - ограничений нет
+type triggerLimiter struct {
+	debouncer *debouncer
+	bucket    *tokenBucket
+	rateLimit *tokenBucket
+}
+
+// ConfigureTrigger задаёт настройки выполнения для триггера. Настройки можно задать и до регистрации событий триггера.
+// Остановить цепочку (event.ErrStop, StopOnError) можно только для событий из следующих групп, поэтому в режиме
+// Parallel остановка не влияет на другие события триггера. Имена с префиксом event.NamespacePrefix зарезервированы
+func (e *eventLoop) ConfigureTrigger(triggerName string, config TriggerConfig) error {
+	if err := event.ValidateTriggerName(triggerName); err != nil {
+		return err
+	}
+	return e.configureTrigger(triggerName, config)
+}
+
+// configureTrigger задаёт настройки по ключу триггера в контейнере, в том числе триггера пространства имён
+func (e *eventLoop) configureTrigger(triggerName string, config TriggerConfig) error {
+	if config.Mode == "" {
+		config.Mode = Parallel
+	}
+	switch config.Mode {
+	case Parallel, Sequential, PriorityTiers:
+	default:
+		return fmt.Errorf("unknown execution mode %q", config.Mode)
+	}
+	debounce, err := config.Debounce.validate()
+	if err != nil {
+		return err
+	}
+	config.Debounce = debounce
+	if err = config.Throttle.validate(); err != nil {
+		return err
+	}
+	if err = config.RateLimit.validate(); err != nil {
+		return err
+	}
+
+	var limiter *triggerLimiter
+	if config.Debounce.Wait > 0 || config.Throttle.Limit > 0 || config.RateLimit.Limit > 0 {
+		limiter = &triggerLimiter{}
+	}
+	if config.Debounce.Wait > 0 {
+		limiter.debouncer = &debouncer{config: config.Debounce}
+	}
+	if config.Throttle.Limit > 0 {
+		limiter.bucket = newTokenBucket(config.Throttle, e.clock.Now())
+	}
+	if config.RateLimit.Limit > 0 {
+		limiter.rateLimit = newTokenBucket(Throttle(config.RateLimit), e.clock.Now())
+	}
+
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.triggerConfigs == nil {
+		e.triggerConfigs = make(map[string]TriggerConfig)
+		e.triggerLimiters = make(map[string]*triggerLimiter)
+	}
+	e.triggerConfigs[triggerName] = config
+	e.triggerLimiters[triggerName] = limiter
+	e.logger.Debugw("Trigger configured", "triggerName", triggerName, "mode", config.Mode)
+	return nil
+}
+
+// GetTriggerConfig возвращает настройки триггера. Для ненастроенного триггера - настройки по умолчанию
+func (e *eventLoop) GetTriggerConfig(triggerName string) TriggerConfig {
+	e.mx.RLock()
+	defer e.mx.RUnlock()
+	return e.triggerConfig(triggerName)
+}
+
+// triggerConfig - GetTriggerConfig без блокировки, вызывающий сам держит e.mx
+func (e *eventLoop) triggerConfig(triggerName string) TriggerConfig {
+	if config, ok := e.triggerConfigs[triggerName]; ok {
+		return config
+	}
+	return TriggerConfig{Mode: Parallel}
+}
+
+// suppress пропускает вызов триггера через его debounce и throttle. by != "" - вызов подавлен и события выполнять не
+// нужно, иначе suppressedCalls - сколько вызовов было подавлено до этого выполнения
+func (e *eventLoop) suppress(
+	ctx context.Context,
+	triggerName string,
+) (suppressedCalls int, by SuppressReason, err error) {
+	e.mx.RLock()
+	limiter := e.triggerLimiters[triggerName]
+	e.mx.RUnlock()
+	if limiter == nil || limiter.debouncer == nil && limiter.bucket == nil {
+		return 0, "", nil
+	}
+
+	if limiter.debouncer != nil {
+		n, suppressed, errDebounce := e.debounce(ctx, limiter.debouncer)
+		if errDebounce != nil || suppressed {
+			return 0, SuppressedByDebounce, errDebounce
+		}
+		suppressedCalls += n
+	}
+	if limiter.bucket != nil {
+		n, ok := limiter.bucket.take(e.clock.Now())
+		if !ok {
+			return 0, SuppressedByThrottle, nil
+		}
+		suppressedCalls += n
+	}
+	return suppressedCalls, "", nil
+}
+
+// executionTiers делит отсортированные по приоритету события на группы, которые выполняются одна за другой. События
+// внутри группы выполняются одновременно. Группы содержат индексы событий в events
+func executionTiers(mode ExecutionMode, events []event.Interface) (tiers [][]int) {
+	for i, ev := range events {
+		switch {
+		case len(tiers) == 0,
+			mode == Sequential,
+			mode == PriorityTiers && events[tiers[len(tiers)-1][0]].GetPriority() != ev.GetPriority():
+			tiers = append(tiers, []int{i})
+		default:
+			tiers[len(tiers)-1] = append(tiers[len(tiers)-1], i)
+		}
+	}
+	return
+}
+
+// runTier выполняет группу событий одновременно и ждёт, пока выполнятся все, кроме AFTER событий: они только
+// планируются. Результаты пишутся в results по индексам событий
+func (e *eventLoop) runTier(ctx context.Context, events []event.Interface, tier []int, results []event.Result) {
+	wg := sync.WaitGroup{}
+	for _, i := range tier {
+		i, ev := i, events[i]
+		if once, err := ev.Once(); err == nil {
+			once.Do(
+				func() {
+					e.removeEvents(ev.GetUUID())
+				},
+			)
+		}
+
+		// AFTER событие ждёт своё время на таймере планировщика и не задерживает вызов триггера
+		if _, afterErr := ev.After(); afterErr == nil {
+			results[i] = e.scheduleAfterEvent(ctx, ev)
+			continue
+		}
+
+		e.logger.Debugw("Start runFunc goroutine", "eventId", ev.GetUUID())
+		wg.Add(1)
+		finish := func(ctx context.Context, result event.Result) event.Result {
+			defer wg.Done()
+			results[i] = e.handleResult(ctx, result)
+			return results[i]
+		}
+		run := func(ctx context.Context) event.Result {
+			return finish(ctx, e.triggerEventFunc(ctx, ev, 0))
+		}
+		// Интервал только запускается или останавливается - воркер пула ему не нужен
+		if _, intervalErr := ev.Interval(); intervalErr == nil {
+			go run(ctx)
+			continue
+		}
+		e.goRun(
+			ctx, run, func(err error) {
+				finish(ctx, e.notExecutedResult(ev, err))
+			},
+		)
+	}
+	wg.Wait()
+}
+
+// haltingEvent ищет в выполненной группе событие, которое останавливает цепочку триггера: вернувшее event.ErrStop,
+// либо упавшее, если включён StopOnError. Возвращает UUID такого события
+func haltingEvent(config TriggerConfig, tier []int, results []event.Result) (string, bool) {
+	for _, i := range tier {
+		if results[i].Status == event.StatusHalted || config.StopOnError && results[i].Err != nil {
+			return results[i].UUID, true
+		}
+	}
+	return "", false
+}
+
+// skipTiers отмечает все события групп как пропущенные
+func skipTiers(events []event.Interface, tiers [][]int, results []event.Result) {
+	for _, tier := range tiers {
+		for _, i := range tier {
+			results[i] = event.Result{
+				UUID: events[i].GetUUID(), Priority: events[i].GetPriority(), Status: event.StatusSkipped,
+			}
+		}
+	}
+}
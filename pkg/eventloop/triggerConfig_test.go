@@ -0,0 +1,277 @@
+package eventloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/YSX/eventloop/pkg/eventloop/clock"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/event"
+	"gitlab.com/YSX/eventloop/pkg/eventloop/internal/eventsContainer"
+)
+
+func Test_eventLoop_ConfigureTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TriggerConfig
+		want    TriggerConfig
+		wantErr bool
+	}{
+		{
+			name:   "Sequential",
+			config: TriggerConfig{Mode: Sequential},
+			want:   TriggerConfig{Mode: Sequential},
+		},
+		{
+			name:   "Default mode",
+			config: TriggerConfig{},
+			want:   TriggerConfig{Mode: Parallel},
+		},
+		{
+			name:    "Unknown mode",
+			config:  TriggerConfig{Mode: "RANDOM"},
+			want:    TriggerConfig{Mode: Parallel},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := &eventLoop{
+					events: eventsContainer.New(), mx: &sync.RWMutex{}, clock: clock.New(), logger: newTestLogger(),
+				}
+				if err := e.ConfigureTrigger("Trig", tt.config); (err != nil) != tt.wantErr {
+					t.Errorf("ConfigureTrigger() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got := e.GetTriggerConfig("Trig"); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("GetTriggerConfig() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_executionTiers(t *testing.T) {
+	var events []event.Interface
+	for _, priority := range []int{3, 2, 2, 1} {
+		ev, _ := event.NewEvent(
+			event.Args{
+				TriggerName: "Trig", Priority: priority, Fun: func(ctx context.Context) string {
+					return ""
+				},
+			},
+		)
+		events = append(events, ev)
+	}
+	tests := []struct {
+		name string
+		mode ExecutionMode
+		want [][]int
+	}{
+		{
+			name: "Parallel",
+			mode: Parallel,
+			want: [][]int{{0, 1, 2, 3}},
+		},
+		{
+			name: "Sequential",
+			mode: Sequential,
+			want: [][]int{{0}, {1}, {2}, {3}},
+		},
+		{
+			name: "Priority tiers",
+			mode: PriorityTiers,
+			want: [][]int{{0}, {1, 2}, {3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := executionTiers(tt.mode, events); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("executionTiers() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_Trigger_ExecutionMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          ExecutionMode
+		wantOrder     []int
+		wantMaxActive int
+	}{
+		{
+			name:          "Parallel",
+			mode:          Parallel,
+			wantMaxActive: 4,
+		},
+		{
+			name:          "Sequential",
+			mode:          Sequential,
+			wantOrder:     []int{3, 2, 2, 1},
+			wantMaxActive: 1,
+		},
+		{
+			name:          "Priority tiers",
+			mode:          PriorityTiers,
+			wantOrder:     []int{3, 2, 2, 1},
+			wantMaxActive: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					mx                   sync.Mutex
+					order                []int
+					active, maxActive    int
+					e                    = NewEventLoop(WithLogger(newTestLogger()))
+					ctx, cancel          = context.WithTimeout(context.Background(), time.Second)
+					newPriorityEventFunc = func(priority int) event.Func {
+						return func(ctx context.Context) string {
+							mx.Lock()
+							order = append(order, priority)
+							active++
+							if active > maxActive {
+								maxActive = active
+							}
+							mx.Unlock()
+
+							time.Sleep(20 * time.Millisecond)
+
+							mx.Lock()
+							active--
+							mx.Unlock()
+							return ""
+						}
+					}
+				)
+				defer cancel()
+
+				for _, priority := range []int{1, 2, 3, 2} {
+					ev, _ := event.NewEvent(
+						event.Args{TriggerName: "Trig", Priority: priority, Fun: newPriorityEventFunc(priority)},
+					)
+					if err := e.RegisterEvent(ctx, ev); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := e.ConfigureTrigger("Trig", TriggerConfig{Mode: tt.mode}); err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err := e.Trigger(ctx, "Trig"); err != nil {
+					t.Fatal(err)
+				}
+				if tt.wantOrder != nil && !reflect.DeepEqual(order, tt.wantOrder) {
+					t.Errorf("Trigger() order = %v, want %v", order, tt.wantOrder)
+				}
+				if maxActive != tt.wantMaxActive {
+					t.Errorf("Trigger() max parallel events = %v, want %v", maxActive, tt.wantMaxActive)
+				}
+			},
+		)
+	}
+}
+
+func Test_eventLoop_Trigger_HaltChain(t *testing.T) {
+	var (
+		errValidation = errors.New("validation")
+		okFun         = func(ctx context.Context) (string, error) {
+			return "OK", nil
+		}
+		stopFun = func(ctx context.Context) (string, error) {
+			return "", fmt.Errorf("wrapped: %w", event.ErrStop)
+		}
+		errFun = func(ctx context.Context) (string, error) {
+			return "", errValidation
+		}
+	)
+	tests := []struct {
+		name string
+		// mid - функция события с приоритетом 2, до него выполняется событие с приоритетом 3, после - с приоритетом 1
+		mid         event.ErrFunc
+		config      TriggerConfig
+		wantStatus  []event.Status
+		wantHaltMid bool
+	}{
+		{
+			name:        "Stop, sequential",
+			mid:         stopFun,
+			config:      TriggerConfig{Mode: Sequential},
+			wantStatus:  []event.Status{event.StatusDone, event.StatusHalted, event.StatusSkipped},
+			wantHaltMid: true,
+		},
+		{
+			name:        "Stop, priority tiers",
+			mid:         stopFun,
+			config:      TriggerConfig{Mode: PriorityTiers},
+			wantStatus:  []event.Status{event.StatusDone, event.StatusHalted, event.StatusSkipped},
+			wantHaltMid: true,
+		},
+		{
+			name:       "Stop, parallel",
+			mid:        stopFun,
+			config:     TriggerConfig{Mode: Parallel},
+			wantStatus: []event.Status{event.StatusDone, event.StatusHalted, event.StatusDone},
+		},
+		{
+			name:       "Error without StopOnError",
+			mid:        errFun,
+			config:     TriggerConfig{Mode: Sequential},
+			wantStatus: []event.Status{event.StatusDone, event.StatusFailed, event.StatusDone},
+		},
+		{
+			name:        "Error with StopOnError",
+			mid:         errFun,
+			config:      TriggerConfig{Mode: Sequential, StopOnError: true},
+			wantStatus:  []event.Status{event.StatusDone, event.StatusFailed, event.StatusSkipped},
+			wantHaltMid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					e           = NewEventLoop(WithLogger(newTestLogger()))
+					ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+					mid, _      = event.NewEvent(event.Args{TriggerName: "Trig", Priority: 2, ErrFun: tt.mid})
+					high, _     = event.NewEvent(event.Args{TriggerName: "Trig", Priority: 3, ErrFun: okFun})
+					low, _      = event.NewEvent(event.Args{TriggerName: "Trig", Priority: 1, ErrFun: okFun})
+				)
+				defer cancel()
+
+				if err := e.RegisterEvent(ctx, low, mid, high); err != nil {
+					t.Fatal(err)
+				}
+				if err := e.ConfigureTrigger("Trig", tt.config); err != nil {
+					t.Fatal(err)
+				}
+
+				got, err := e.Trigger(ctx, "Trig")
+				if err != nil {
+					t.Fatal(err)
+				}
+				var gotStatus []event.Status
+				for _, res := range got.Events {
+					gotStatus = append(gotStatus, res.Status)
+				}
+				if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+					t.Errorf("Trigger() statuses = %v, want %v", gotStatus, tt.wantStatus)
+				}
+				if (got.HaltedBy == mid.GetUUID()) != tt.wantHaltMid {
+					t.Errorf("Trigger() HaltedBy = %v, want halted by mid %v", got.HaltedBy, tt.wantHaltMid)
+				}
+				if got.Events[2].UUID != low.GetUUID() {
+					t.Errorf("Trigger() last event = %v, want %v", got.Events[2].UUID, low.GetUUID())
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,22 @@
+package logger
+
+// nop - логгер, который ничего не пишет. Используется, когда логгер не передан
+type nop struct{}
+
+func NewNop() Interface {
+	return nop{}
+}
+
+func (nop) Debugf(string, ...interface{}) {}
+func (nop) Debugw(string, ...interface{}) {}
+func (nop) Error(...interface{})          {}
+func (nop) Errorf(string, ...interface{}) {}
+func (nop) Errorw(string, ...interface{}) {}
+func (nop) Info(...interface{})           {}
+func (nop) Infof(string, ...interface{})  {}
+func (nop) Infow(string, ...interface{})  {}
+func (nop) Warn(...interface{})           {}
+func (nop) Warnf(string, ...interface{})  {}
+func (nop) Warnw(string, ...interface{})  {}
+func (nop) Level() string                 { return "" }
+func (nop) Sync() error                   { return nil }